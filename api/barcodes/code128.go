@@ -0,0 +1,106 @@
+package barcodes
+
+import (
+	"fmt"
+)
+
+/*
+Every Code128 symbol is written as the widths of its bars
+and spaces, starting with a bar. The last four entries are
+the start codes for code set A, B and C and the stop code.
+*/
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+func appendWidths(modules []bool, widths string) []bool {
+	dark := true
+
+	for _, width := range widths {
+		for i := 0; i < int(width-'0'); i++ {
+			modules = append(modules, dark)
+		}
+
+		dark = !dark
+	}
+
+	return modules
+}
+
+func isEvenDigits(barcode string) bool {
+	if len(barcode)%2 != 0 {
+		return false
+	}
+
+	for _, character := range barcode {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Barcodes made up of an even number of digits are encoded
+with code set C which packs two digits into every symbol.
+Everything else uses code set B which covers printable
+ASCII.
+*/
+func EncodeCode128(barcode string) (Symbol, error) {
+	if len(barcode) == 0 {
+		return Symbol{}, fmt.Errorf("Can't encode an empty barcode as Code128")
+	}
+
+	values := []int{}
+
+	if isEvenDigits(barcode) {
+		values = append(values, code128StartC)
+
+		for i := 0; i < len(barcode); i += 2 {
+			values = append(values, int(barcode[i]-'0')*10+int(barcode[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+
+		for _, character := range barcode {
+			if character < 32 || character > 127 {
+				return Symbol{}, fmt.Errorf("Barcode (%s) contains characters that can't be encoded as Code128", barcode)
+			}
+
+			values = append(values, int(character-32))
+		}
+	}
+
+	checksum := values[0]
+
+	for i := 1; i < len(values); i++ {
+		checksum += i * values[i]
+	}
+
+	values = append(values, checksum%103, code128Stop)
+
+	modules := []bool{}
+
+	for _, value := range values {
+		modules = appendWidths(modules, code128Patterns[value])
+	}
+
+	return newLinearSymbol(modules), nil
+}
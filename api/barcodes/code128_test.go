@@ -0,0 +1,113 @@
+package barcodes
+
+import (
+	"reflect"
+	"testing"
+)
+
+/*
+Reads the symbol values back out of the modules by turning
+them into runs of bar and space widths and looking every
+six of them up in the pattern table. The stop code has a
+seventh bar.
+*/
+func decodeCode128(t *testing.T, modules []bool) []int {
+	widths := []byte{}
+
+	for i := 0; i < len(modules); {
+		start := i
+
+		for i < len(modules) && modules[i] == modules[start] {
+			i++
+		}
+
+		widths = append(widths, byte('0'+i-start))
+	}
+
+	values := []int{}
+
+	for len(widths) > 0 {
+		length := 6
+
+		if len(widths) == 7 {
+			length = 7
+		}
+
+		if len(widths) < length {
+			t.Fatalf("Trailing widths %s", widths)
+		}
+
+		pattern := string(widths[:length])
+		widths = widths[length:]
+		found := false
+
+		for value, candidate := range code128Patterns {
+			if candidate == pattern {
+				values = append(values, value)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Fatalf("Unknown pattern %s", pattern)
+		}
+	}
+
+	return values
+}
+
+func TestEncodeCode128(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    []int
+	}{
+		// Even number of digits, code set C
+		{"1234", []int{code128StartC, 12, 34, 82, code128Stop}},
+		{"00", []int{code128StartC, 0, 2, code128Stop}},
+
+		// Odd number of digits falls back to code set B
+		{"123", []int{code128StartB, 17, 18, 19, 8, code128Stop}},
+		{"Ab1", []int{code128StartB, 33, 66, 17, 11, code128Stop}},
+		{"SKU-1", []int{code128StartB, 51, 43, 53, 13, 17, 22, code128Stop}},
+	}
+
+	for _, test := range tests {
+		symbol, err := EncodeCode128(test.barcode)
+
+		if err != nil {
+			t.Errorf("EncodeCode128(%s) returned error %v", test.barcode, err)
+			continue
+		}
+
+		got := decodeCode128(t, symbol.Modules[0])
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("EncodeCode128(%s) = %v, want %v", test.barcode, got, test.want)
+		}
+
+		// Every symbol is 11 modules wide and the stop code 13
+		wantWidth := (len(test.want)-1)*11 + 13
+
+		if symbol.width() != wantWidth {
+			t.Errorf("EncodeCode128(%s) is %d modules wide, want %d", test.barcode, symbol.width(), wantWidth)
+		}
+	}
+}
+
+func TestEncodeCode128Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"café",
+		"tab\t",
+		"line\nbreak",
+	}
+
+	for _, barcode := range tests {
+		_, err := EncodeCode128(barcode)
+
+		if err == nil {
+			t.Errorf("EncodeCode128(%q) should have failed", barcode)
+		}
+	}
+}
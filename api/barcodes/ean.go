@@ -0,0 +1,81 @@
+package barcodes
+
+import (
+	"api/util"
+	"fmt"
+)
+
+var eanLeftOdd = []string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+var eanLeftEven = []string{
+	"0100111", "0110011", "0011011", "0100001", "0011101",
+	"0111001", "0000101", "0010001", "0001001", "0010111",
+}
+
+var eanRight = []string{
+	"1110010", "1100110", "1101100", "1000010", "1011100",
+	"1001110", "1010000", "1000100", "1001000", "1110100",
+}
+
+/*
+The first digit of an EAN-13 barcode is not drawn as bars.
+Instead it decides which of the following six digits use
+the even parity patterns, marked with a G here.
+*/
+var eanFirstDigitParity = []string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+func appendPattern(modules []bool, pattern string) []bool {
+	for _, module := range pattern {
+		modules = append(modules, module == '1')
+	}
+
+	return modules
+}
+
+func EncodeEAN13(barcode string) (Symbol, error) {
+	if util.DetectBarcodeType(barcode) != util.BarcodeTypeEAN13 {
+		return Symbol{}, fmt.Errorf("Barcode (%s) is not a valid EAN-13 barcode", barcode)
+	}
+
+	parity := eanFirstDigitParity[barcode[0]-'0']
+
+	modules := appendPattern(nil, "101")
+
+	for i := 1; i <= 6; i++ {
+		digit := barcode[i] - '0'
+
+		if parity[i-1] == 'G' {
+			modules = appendPattern(modules, eanLeftEven[digit])
+		} else {
+			modules = appendPattern(modules, eanLeftOdd[digit])
+		}
+	}
+
+	modules = appendPattern(modules, "01010")
+
+	for i := 7; i <= 12; i++ {
+		modules = appendPattern(modules, eanRight[barcode[i]-'0'])
+	}
+
+	modules = appendPattern(modules, "101")
+
+	return newLinearSymbol(modules), nil
+}
+
+/*
+UPC-A is drawn exactly like an EAN-13 barcode that starts
+with a zero.
+*/
+func EncodeUPCA(barcode string) (Symbol, error) {
+	if util.DetectBarcodeType(barcode) != util.BarcodeTypeUPCA {
+		return Symbol{}, fmt.Errorf("Barcode (%s) is not a valid UPC-A barcode", barcode)
+	}
+
+	return EncodeEAN13("0" + barcode)
+}
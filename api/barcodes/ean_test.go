@@ -0,0 +1,91 @@
+package barcodes
+
+import "testing"
+
+func modulesToString(modules []bool) string {
+	result := make([]byte, len(modules))
+
+	for i, dark := range modules {
+		result[i] = '0'
+
+		if dark {
+			result[i] = '1'
+		}
+	}
+
+	return string(result)
+}
+
+func TestEncodeEAN13(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    string
+	}{
+		{
+			"4006381333931",
+			"10100011010100111010111101111010001001011001101010100001010000101000010111010010000101100110101",
+		},
+		{
+			"0036000291452",
+			"10100011010111101010111100011010001101000110101010110110011101001100110101110010011101101100101",
+		},
+	}
+
+	for _, test := range tests {
+		symbol, err := EncodeEAN13(test.barcode)
+
+		if err != nil {
+			t.Errorf("EncodeEAN13(%s) returned error %v", test.barcode, err)
+			continue
+		}
+
+		if !symbol.Linear || len(symbol.Modules) != 1 {
+			t.Errorf("EncodeEAN13(%s) is not a single linear row", test.barcode)
+			continue
+		}
+
+		got := modulesToString(symbol.Modules[0])
+
+		if got != test.want {
+			t.Errorf("EncodeEAN13(%s) = %s, want %s", test.barcode, got, test.want)
+		}
+	}
+}
+
+func TestEncodeEAN13Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"4006381333932",
+		"400638133393",
+		"036000291452",
+		"40063813339A1",
+	}
+
+	for _, barcode := range tests {
+		_, err := EncodeEAN13(barcode)
+
+		if err == nil {
+			t.Errorf("EncodeEAN13(%s) should have failed", barcode)
+		}
+	}
+}
+
+func TestEncodeUPCA(t *testing.T) {
+	upca, err := EncodeUPCA("036000291452")
+
+	if err != nil {
+		t.Fatalf("EncodeUPCA returned error %v", err)
+	}
+
+	ean, _ := EncodeEAN13("0036000291452")
+
+	if modulesToString(upca.Modules[0]) != modulesToString(ean.Modules[0]) {
+		t.Errorf("UPC-A should be drawn like the EAN-13 barcode with a leading zero")
+	}
+
+	_, err = EncodeUPCA("036000291453")
+
+	if err == nil {
+		t.Errorf("EncodeUPCA should fail on a wrong check digit")
+	}
+}
@@ -0,0 +1,581 @@
+package barcodes
+
+import (
+	"fmt"
+)
+
+/*
+QR codes are always encoded in byte mode with error
+correction level M. Barcodes are at most 32 characters so
+we only need to support the first ten versions.
+
+Every version lists how many error correction codewords
+each block has, the groups of blocks as
+{number of blocks, data codewords per block} and the
+positions of its alignment patterns.
+*/
+type qrVersion struct {
+	ecCodewordsPerBlock int
+	groups              [][2]int
+	alignment           []int
+}
+
+var qrVersions = []qrVersion{
+	{10, [][2]int{{1, 16}}, nil},
+	{16, [][2]int{{1, 28}}, []int{6, 18}},
+	{26, [][2]int{{1, 44}}, []int{6, 22}},
+	{18, [][2]int{{2, 32}}, []int{6, 26}},
+	{24, [][2]int{{2, 43}}, []int{6, 30}},
+	{16, [][2]int{{4, 27}}, []int{6, 34}},
+	{18, [][2]int{{4, 31}}, []int{6, 22, 38}},
+	{22, [][2]int{{2, 38}, {2, 39}}, []int{6, 24, 42}},
+	{22, [][2]int{{3, 36}, {2, 37}}, []int{6, 26, 46}},
+	{26, [][2]int{{4, 43}, {1, 44}}, []int{6, 28, 50}},
+}
+
+func (version qrVersion) dataCodewords() int {
+	count := 0
+
+	for _, group := range version.groups {
+		count += group[0] * group[1]
+	}
+
+	return count
+}
+
+type qrMatrix struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newQRMatrix(size int) *qrMatrix {
+	matrix := qrMatrix{
+		size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+
+	for i := 0; i < size; i++ {
+		matrix.modules[i] = make([]bool, size)
+		matrix.isFunction[i] = make([]bool, size)
+	}
+
+	return &matrix
+}
+
+func (matrix *qrMatrix) setFunction(x int, y int, dark bool) {
+	matrix.modules[y][x] = dark
+	matrix.isFunction[y][x] = true
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+/*
+The finder pattern is drawn together with its white
+separator which is why we go four modules out from the
+center.
+*/
+func (matrix *qrMatrix) drawFinder(centerX int, centerY int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x := centerX + dx
+			y := centerY + dy
+
+			if x < 0 || y < 0 || x >= matrix.size || y >= matrix.size {
+				continue
+			}
+
+			distance := max(abs(dx), abs(dy))
+			matrix.setFunction(x, y, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (matrix *qrMatrix) drawAlignment(centerX int, centerY int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			matrix.setFunction(centerX+dx, centerY+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func (matrix *qrMatrix) drawFunctionPatterns(version int) {
+	size := matrix.size
+
+	for i := 0; i < size; i++ {
+		matrix.setFunction(6, i, i%2 == 0)
+		matrix.setFunction(i, 6, i%2 == 0)
+	}
+
+	matrix.drawFinder(3, 3)
+	matrix.drawFinder(size-4, 3)
+	matrix.drawFinder(3, size-4)
+
+	alignment := qrVersions[version-1].alignment
+	last := len(alignment) - 1
+
+	for i, x := range alignment {
+		for j, y := range alignment {
+			overlapsFinder := (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0)
+
+			if !overlapsFinder {
+				matrix.drawAlignment(x, y)
+			}
+		}
+	}
+
+	// Reserved for now, the real format bits are drawn once the mask is picked
+	matrix.drawFormatBits(0)
+
+	if version >= 7 {
+		matrix.drawVersionBits(version)
+	}
+}
+
+func (matrix *qrMatrix) drawFormatBits(mask int) {
+	size := matrix.size
+
+	// Error correction level M is 00 in the format bits
+	data := mask
+	remainder := data
+
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+
+	bits := (data<<10 | remainder) ^ 0x5412
+
+	bit := func(i int) bool {
+		return (bits>>uint(i))&1 != 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		matrix.setFunction(8, i, bit(i))
+	}
+
+	matrix.setFunction(8, 7, bit(6))
+	matrix.setFunction(8, 8, bit(7))
+	matrix.setFunction(7, 8, bit(8))
+
+	for i := 9; i < 15; i++ {
+		matrix.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		matrix.setFunction(size-1-i, 8, bit(i))
+	}
+
+	for i := 8; i < 15; i++ {
+		matrix.setFunction(8, size-15+i, bit(i))
+	}
+
+	// The dark module is always there no matter the format
+	matrix.setFunction(8, size-8, true)
+}
+
+func (matrix *qrMatrix) drawVersionBits(version int) {
+	remainder := version
+
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+
+	bits := version<<12 | remainder
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a := matrix.size - 11 + i%3
+		b := i / 3
+
+		matrix.setFunction(a, b, dark)
+		matrix.setFunction(b, a, dark)
+	}
+}
+
+/*
+The codewords are placed in two module wide columns that
+zigzag up and down the matrix from the bottom right corner,
+skipping the vertical timing pattern.
+*/
+func (matrix *qrMatrix) drawCodewords(codewords []byte) {
+	size := matrix.size
+	i := 0
+
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		upward := (right+1)&2 == 0
+
+		for vertical := 0; vertical < size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vertical
+
+				if upward {
+					y = size - 1 - vertical
+				}
+
+				if matrix.isFunction[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+
+				matrix.modules[y][x] = (codewords[i>>3]>>uint(7-(i&7)))&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+func isMasked(mask int, x int, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Applying the same mask twice removes it again
+func (matrix *qrMatrix) applyMask(mask int) {
+	for y := 0; y < matrix.size; y++ {
+		for x := 0; x < matrix.size; x++ {
+			if !matrix.isFunction[y][x] && isMasked(mask, x, y) {
+				matrix.modules[y][x] = !matrix.modules[y][x]
+			}
+		}
+	}
+}
+
+/*
+The penalty follows the four rules from the QR
+specification. The mask with the lowest penalty is the one
+that is easiest for scanners to read.
+*/
+func (matrix *qrMatrix) getPenalty() int {
+	size := matrix.size
+	penalty := 0
+
+	at := func(x int, y int, vertical bool) bool {
+		if vertical {
+			return matrix.modules[x][y]
+		}
+
+		return matrix.modules[y][x]
+	}
+
+	finderLike := []bool{true, false, true, true, true, false, true}
+
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			run := 1
+
+			for x := 1; x <= size; x++ {
+				if x < size && at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+
+				run = 1
+			}
+
+			for x := 0; x+7 <= size; x++ {
+				matches := true
+
+				for i, dark := range finderLike {
+					if at(x+i, y, vertical) != dark {
+						matches = false
+						break
+					}
+				}
+
+				if !matches {
+					continue
+				}
+
+				lightBefore := true
+				lightAfter := true
+
+				for i := 1; i <= 4; i++ {
+					if x-i >= 0 && at(x-i, y, vertical) {
+						lightBefore = false
+					}
+
+					if x+6+i < size && at(x+6+i, y, vertical) {
+						lightAfter = false
+					}
+				}
+
+				if lightBefore || lightAfter {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if matrix.modules[y][x] {
+				dark++
+			}
+
+			if x+1 < size && y+1 < size {
+				color := matrix.modules[y][x]
+
+				if color == matrix.modules[y][x+1] &&
+					color == matrix.modules[y+1][x] &&
+					color == matrix.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	total := size * size
+	penalty += abs(dark*20-total*10) / total * 10
+
+	return penalty
+}
+
+func gfMultiply(x byte, y byte) byte {
+	z := 0
+
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+func getReedSolomonDivisor(degree int) []byte {
+	divisor := make([]byte, degree)
+	divisor[degree-1] = 1
+
+	root := byte(1)
+
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			divisor[j] = gfMultiply(divisor[j], root)
+
+			if j+1 < degree {
+				divisor[j] ^= divisor[j+1]
+			}
+		}
+
+		root = gfMultiply(root, 0x02)
+	}
+
+	return divisor
+}
+
+func getReedSolomonRemainder(data []byte, divisor []byte) []byte {
+	remainder := make([]byte, len(divisor))
+
+	for _, codeword := range data {
+		factor := codeword ^ remainder[0]
+
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+
+		for i := range remainder {
+			remainder[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+
+	return remainder
+}
+
+type bitBuffer struct {
+	bits []bool
+}
+
+func (buffer *bitBuffer) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		buffer.bits = append(buffer.bits, (value>>uint(i))&1 != 0)
+	}
+}
+
+func (buffer *bitBuffer) bytes() []byte {
+	result := make([]byte, len(buffer.bits)/8)
+
+	for i, bit := range buffer.bits {
+		if bit {
+			result[i>>3] |= 1 << uint(7-(i&7))
+		}
+	}
+
+	return result
+}
+
+func getQRDataCodewords(data []byte, version int) []byte {
+	capacity := qrVersions[version-1].dataCodewords() * 8
+
+	countBits := 8
+
+	if version >= 10 {
+		countBits = 16
+	}
+
+	buffer := bitBuffer{}
+	buffer.append(0x4, 4)
+	buffer.append(len(data), countBits)
+
+	for _, value := range data {
+		buffer.append(int(value), 8)
+	}
+
+	terminator := capacity - len(buffer.bits)
+
+	if terminator > 4 {
+		terminator = 4
+	}
+
+	buffer.append(0, terminator)
+	buffer.append(0, (8-len(buffer.bits)%8)%8)
+
+	for pad := 0xEC; len(buffer.bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		buffer.append(pad, 8)
+	}
+
+	return buffer.bytes()
+}
+
+/*
+The data is split into blocks that each get their own error
+correction. The blocks are then interleaved codeword by
+codeword so that damage to one area of the code is spread
+out over all of them.
+*/
+func getQRCodewords(data []byte, version int) []byte {
+	info := qrVersions[version-1]
+	divisor := getReedSolomonDivisor(info.ecCodewordsPerBlock)
+
+	dataBlocks := [][]byte{}
+	ecBlocks := [][]byte{}
+
+	offset := 0
+
+	for _, group := range info.groups {
+		for i := 0; i < group[0]; i++ {
+			block := data[offset : offset+group[1]]
+			offset += group[1]
+
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, getReedSolomonRemainder(block, divisor))
+		}
+	}
+
+	codewords := []byte{}
+
+	longestBlock := len(dataBlocks[len(dataBlocks)-1])
+
+	for i := 0; i < longestBlock; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				codewords = append(codewords, block[i])
+			}
+		}
+	}
+
+	for i := 0; i < info.ecCodewordsPerBlock; i++ {
+		for _, block := range ecBlocks {
+			codewords = append(codewords, block[i])
+		}
+	}
+
+	return codewords
+}
+
+func encodeQRWithMask(text string, mask int) (Symbol, error) {
+	data := []byte(text)
+	version := 0
+
+	for i, info := range qrVersions {
+		countBits := 8
+
+		if i+1 >= 10 {
+			countBits = 16
+		}
+
+		if 4+countBits+len(data)*8 <= info.dataCodewords()*8 {
+			version = i + 1
+			break
+		}
+	}
+
+	if version == 0 {
+		return Symbol{}, fmt.Errorf("Barcode (%s) is too long to encode as a QR code", text)
+	}
+
+	matrix := newQRMatrix(17 + 4*version)
+	matrix.drawFunctionPatterns(version)
+	matrix.drawCodewords(getQRCodewords(getQRDataCodewords(data, version), version))
+
+	if mask < 0 {
+		lowestPenalty := -1
+
+		for candidate := 0; candidate < 8; candidate++ {
+			matrix.applyMask(candidate)
+			matrix.drawFormatBits(candidate)
+
+			penalty := matrix.getPenalty()
+
+			if lowestPenalty < 0 || penalty < lowestPenalty {
+				lowestPenalty = penalty
+				mask = candidate
+			}
+
+			matrix.applyMask(candidate)
+		}
+	}
+
+	matrix.applyMask(mask)
+	matrix.drawFormatBits(mask)
+
+	return Symbol{
+		Modules: matrix.modules,
+	}, nil
+}
+
+func EncodeQR(text string) (Symbol, error) {
+	if len(text) == 0 {
+		return Symbol{}, fmt.Errorf("Can't encode an empty barcode as a QR code")
+	}
+
+	return encodeQRWithMask(text, -1)
+}
@@ -0,0 +1,181 @@
+package barcodes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeQRSize(t *testing.T) {
+	tests := []struct {
+		text string
+		size int
+	}{
+		{"1", 21},
+		{strings.Repeat("a", 14), 21},
+		{strings.Repeat("a", 15), 25},
+		{strings.Repeat("a", 32), 29},
+		{strings.Repeat("a", 213), 57},
+	}
+
+	for _, test := range tests {
+		symbol, err := EncodeQR(test.text)
+
+		if err != nil {
+			t.Errorf("EncodeQR of %d characters returned error %v", len(test.text), err)
+			continue
+		}
+
+		if symbol.Linear {
+			t.Errorf("EncodeQR of %d characters should not be linear", len(test.text))
+		}
+
+		if len(symbol.Modules) != test.size || symbol.width() != test.size {
+			t.Errorf(
+				"EncodeQR of %d characters is %dx%d, want %dx%d",
+				len(test.text),
+				symbol.width(),
+				len(symbol.Modules),
+				test.size,
+				test.size,
+			)
+		}
+	}
+}
+
+func TestEncodeQRInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		strings.Repeat("a", 214),
+	}
+
+	for _, text := range tests {
+		_, err := EncodeQR(text)
+
+		if err == nil {
+			t.Errorf("EncodeQR of %d characters should have failed", len(text))
+		}
+	}
+}
+
+/*
+Checks the parts of the symbol every reader looks for first:
+the finder in the top left corner, the timing pattern, the
+dark module and format bits that decode to level M with a
+valid BCH code in both of their copies.
+*/
+func TestEncodeQRFunctionPatterns(t *testing.T) {
+	for _, text := range []string{"4006381333931", "https://example.com/p/1"} {
+		symbol, err := EncodeQR(text)
+
+		if err != nil {
+			t.Fatalf("EncodeQR(%s) returned error %v", text, err)
+		}
+
+		modules := symbol.Modules
+		size := len(modules)
+
+		finder := []string{
+			"1111111",
+			"1000001",
+			"1011101",
+			"1011101",
+			"1011101",
+			"1000001",
+			"1111111",
+		}
+
+		for y, row := range finder {
+			if modulesToString(modules[y][:7]) != row {
+				t.Errorf("EncodeQR(%s) finder row %d = %s, want %s", text, y, modulesToString(modules[y][:7]), row)
+			}
+		}
+
+		for i := 8; i < size-8; i++ {
+			if modules[6][i] != (i%2 == 0) || modules[i][6] != (i%2 == 0) {
+				t.Errorf("EncodeQR(%s) timing pattern is broken at %d", text, i)
+			}
+		}
+
+		if !modules[size-8][8] {
+			t.Errorf("EncodeQR(%s) is missing the dark module", text)
+		}
+
+		first := 0
+		second := 0
+
+		for i := 0; i <= 5; i++ {
+			first |= boolToBit(modules[i][8]) << uint(i)
+		}
+
+		first |= boolToBit(modules[7][8]) << 6
+		first |= boolToBit(modules[8][8]) << 7
+		first |= boolToBit(modules[8][7]) << 8
+
+		for i := 9; i < 15; i++ {
+			first |= boolToBit(modules[8][14-i]) << uint(i)
+		}
+
+		for i := 0; i < 8; i++ {
+			second |= boolToBit(modules[8][size-1-i]) << uint(i)
+		}
+
+		for i := 8; i < 15; i++ {
+			second |= boolToBit(modules[size-15+i][8]) << uint(i)
+		}
+
+		if first != second {
+			t.Errorf("EncodeQR(%s) format bits differ between copies: %015b and %015b", text, first, second)
+		}
+
+		format := first ^ 0x5412
+		data := format >> 10
+
+		if data>>3 != 0 {
+			t.Errorf("EncodeQR(%s) error correction level is not M", text)
+		}
+
+		remainder := data
+
+		for i := 0; i < 10; i++ {
+			remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+		}
+
+		if format&0x3FF != remainder {
+			t.Errorf("EncodeQR(%s) format bits have an invalid BCH code", text)
+		}
+	}
+}
+
+func boolToBit(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+func TestGetQRDataCodewords(t *testing.T) {
+	got := getQRDataCodewords([]byte("hello"), 1)
+
+	want := []byte{64, 86, 134, 86, 198, 198, 240, 236, 17, 236, 17, 236, 17, 236, 17, 236}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getQRDataCodewords(hello) = %v, want %v", got, want)
+	}
+}
+
+/*
+The data and error correction codewords of HELLO WORLD as a
+1-M symbol, the example most QR code guides walk through.
+*/
+func TestGetReedSolomonRemainder(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	got := getReedSolomonRemainder(data, getReedSolomonDivisor(len(want)))
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getReedSolomonRemainder = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package barcodes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+/*
+A symbol is an encoded barcode that is ready to be drawn.
+Linear barcodes like EAN-13 and Code128 only have a single
+row of modules that gets stretched to the bar height while
+a QR code is a square grid of modules.
+*/
+type Symbol struct {
+	Modules [][]bool
+	Linear  bool
+}
+
+/*
+ModuleSize is the width in pixels of the narrowest bar or
+of a single QR module. BarHeight is only used for linear
+barcodes and QuietZone is the white margin counted in
+modules.
+*/
+type RenderOptions struct {
+	ModuleSize int
+	BarHeight  int
+	QuietZone  int
+}
+
+func newLinearSymbol(modules []bool) Symbol {
+	return Symbol{
+		Modules: [][]bool{modules},
+		Linear:  true,
+	}
+}
+
+func (symbol Symbol) width() int {
+	return len(symbol.Modules[0])
+}
+
+func (symbol Symbol) getImageSize(options RenderOptions) (int, int) {
+	margin := 2 * options.QuietZone * options.ModuleSize
+	width := symbol.width()*options.ModuleSize + margin
+
+	if symbol.Linear {
+		return width, options.BarHeight + margin
+	}
+
+	return width, len(symbol.Modules)*options.ModuleSize + margin
+}
+
+/*
+Calls draw for every horizontal run of dark modules with
+the position and size of the run in pixels.
+*/
+func (symbol Symbol) forEachRun(
+	options RenderOptions,
+	draw func(x int, y int, width int, height int),
+) {
+
+	offset := options.QuietZone * options.ModuleSize
+
+	rowHeight := options.ModuleSize
+
+	if symbol.Linear {
+		rowHeight = options.BarHeight
+	}
+
+	for row, modules := range symbol.Modules {
+		for column := 0; column < len(modules); column++ {
+			if !modules[column] {
+				continue
+			}
+
+			start := column
+
+			for column < len(modules) && modules[column] {
+				column++
+			}
+
+			draw(
+				offset+start*options.ModuleSize,
+				offset+row*rowHeight,
+				(column-start)*options.ModuleSize,
+				rowHeight,
+			)
+		}
+	}
+}
+
+func RenderSVG(symbol Symbol, options RenderOptions) []byte {
+	width, height := symbol.getImageSize(options)
+
+	svg := bytes.Buffer{}
+
+	fmt.Fprintf(
+		&svg,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		width,
+		height,
+		width,
+		height,
+	)
+
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#fff"/>`, width, height)
+
+	symbol.forEachRun(options, func(x int, y int, runWidth int, runHeight int) {
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="#000"/>`, x, y, runWidth, runHeight)
+	})
+
+	svg.WriteString("</svg>")
+
+	return svg.Bytes()
+}
+
+func RenderPNG(symbol Symbol, options RenderOptions) ([]byte, error) {
+	width, height := symbol.getImageSize(options)
+
+	palette := color.Palette{color.White, color.Black}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+
+	symbol.forEachRun(options, func(x int, y int, runWidth int, runHeight int) {
+		for py := y; py < y+runHeight; py++ {
+			for px := x; px < x+runWidth; px++ {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	})
+
+	buffer := bytes.Buffer{}
+
+	err := png.Encode(&buffer, img)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,186 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+/*
+A very small PDF writer that only knows how to put text and
+filled rectangles on pages, which is all we need for labels.
+
+Text is set in the built in Helvetica fonts so nothing has
+to be embedded. Those fonts use the WinAnsi encoding so any
+character outside of Latin-1 is replaced with a question
+mark.
+
+All coordinates are in points with the origin in the top
+left corner of the page.
+*/
+type PDF struct {
+	pages []*PDFPage
+}
+
+type PDFPage struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+const (
+	FontRegular = "F1"
+	FontBold    = "F2"
+)
+
+const MillimetersToPoints = 72 / 25.4
+
+func NewPDF() *PDF {
+	return &PDF{}
+}
+
+func (pdf *PDF) AddPage(width float64, height float64) *PDFPage {
+	page := PDFPage{
+		width:  width,
+		height: height,
+	}
+
+	pdf.pages = append(pdf.pages, &page)
+
+	return &page
+}
+
+func encodeText(text string) string {
+	encoded := strings.Builder{}
+
+	for _, character := range text {
+		if character < 32 || (character >= 127 && character < 160) || character > 255 {
+			character = '?'
+		}
+
+		switch character {
+		case '(', ')', '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteByte(byte(character))
+		default:
+			encoded.WriteByte(byte(character))
+		}
+	}
+
+	return encoded.String()
+}
+
+/*
+Helvetica does not have a fixed width so this is only an
+estimate based on its average character width. It is good
+enough to decide where a line has to be cut off.
+*/
+func EstimateTextWidth(text string, fontSize float64) float64 {
+	return float64(len([]rune(text))) * fontSize * 0.55
+}
+
+// Text draws a single line of text with its baseline at y
+func (page *PDFPage) Text(
+	x float64,
+	y float64,
+	font string,
+	fontSize float64,
+	text string,
+) {
+
+	fmt.Fprintf(
+		&page.content,
+		"BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font,
+		fontSize,
+		x,
+		page.height-y,
+		encodeText(text),
+	)
+}
+
+func (page *PDFPage) Rect(
+	x float64,
+	y float64,
+	width float64,
+	height float64,
+) {
+
+	fmt.Fprintf(
+		&page.content,
+		"%.3f %.3f %.3f %.3f re f\n",
+		x,
+		page.height-y-height,
+		width,
+		height,
+	)
+}
+
+/*
+Every object is written with its byte offset recorded so the
+cross reference table at the end can point straight to it.
+Objects 1 to 4 are the catalog, the page tree and the two
+fonts, after that every page takes two objects, the page
+itself followed by its content stream.
+*/
+func (pdf *PDF) Bytes() []byte {
+	output := bytes.Buffer{}
+	offsets := []int{}
+
+	writeObject := func(body string) {
+		offsets = append(offsets, output.Len())
+		fmt.Fprintf(&output, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	output.WriteString("%PDF-1.4\n")
+
+	kids := []string{}
+
+	for i := range pdf.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf(
+		"<< /Type /Pages /Kids [%s] /Count %d >>",
+		strings.Join(kids, " "),
+		len(pdf.pages),
+	))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pdf.pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+				"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			page.width,
+			page.height,
+			FontRegular,
+			FontBold,
+			6+i*2,
+		))
+
+		writeObject(fmt.Sprintf(
+			"<< /Length %d >>\nstream\n%sendstream",
+			page.content.Len(),
+			page.content.String(),
+		))
+	}
+
+	xrefOffset := output.Len()
+
+	fmt.Fprintf(&output, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&output, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(
+		&output,
+		"trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1,
+		xrefOffset,
+	)
+
+	return output.Bytes()
+}
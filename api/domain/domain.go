@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"errors"
 	"net/http"
+	"time"
 )
 
 /*
@@ -14,14 +16,60 @@ needs to adhere to and simplifies future updates.
 
 type ProductId = uint32
 
+type CategoryId = uint32
+
+type BrandId = uint32
+
+var ErrBarcodeRangeExhausted = errors.New("There are no barcodes left to generate for the configured prefix")
+
+var ErrInsufficientStock = errors.New("There is not enough stock available to reserve")
+
+var ErrReservationNotActive = errors.New("The reservation has already been confirmed, released or has expired")
+
+var ErrStocktakeNotOpen = errors.New("The stocktake has already been committed")
+
+/*
+The label is never stored on the product, it is looked up
+from the attribute definition in the language the client
+asked for.
+*/
 type ProductAttribute struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeInteger = "integer"
+	AttributeTypeDecimal = "decimal"
+	AttributeTypeBoolean = "boolean"
+)
+
+/*
+A definition describes what values an attribute with the
+same name is allowed to have. Attributes without a
+definition are still free form.
+*/
+type AttributeDefinition struct {
+	Name          string   `json:"name"`
+	DataType      string   `json:"dataType"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	Unit          *string  `json:"unit,omitempty"`
+	Required      bool     `json:"required"`
+
+	/*
+		Display labels for the allowed values keyed by value
+		and then by locale, for example {"XS": {"en": "Extra small"}}.
+		The allowed values are kept in the order they should
+		be displayed in.
+	*/
+	ValueLabels map[string]map[string]string `json:"valueLabels,omitempty"`
 }
 
 type ProductBarcode struct {
-	ProductID ProductId
-	Barcode   string
+	ProductID ProductId `json:"productId"`
+	Barcode   string    `json:"barcode"`
 }
 
 type ProductSku struct {
@@ -29,25 +77,279 @@ type ProductSku struct {
 	Sku       string
 }
 
+/*
+A translation only has to contain the fields that differ
+from the product itself, anything left out falls back to
+the next locale and finally to the product.
+*/
+type ProductTranslation struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+/*
+Drafts aren't for sale yet and discontinued products can't
+be ordered again but are still sold off and keep their
+barcodes. Archiving a product releases its barcodes.
+*/
+const (
+	ProductStatusDraft        = "draft"
+	ProductStatusActive       = "active"
+	ProductStatusDiscontinued = "discontinued"
+	ProductStatusArchived     = "archived"
+)
+
 type Product struct {
-	ProductID   ProductId          `json:"productId,omitempty"`
-	Title       string             `json:"title,omitempty"`
-	Sku         string             `json:"sku,omitempty"`
-	Barcodes    []string           `json:"barcodes,omitempty"`
-	Description *string            `json:"description,omitempty"`
-	Price       string             `json:"price,omitempty"`
-	Created     int64              `json:"created,omitempty"`
-	LastUpdated *int64             `json:"lastUpdated,omitempty"`
-	Attributes  []ProductAttribute `json:"attributes,omitempty"`
+	ProductID    ProductId          `json:"productId,omitempty"`
+	Title        string             `json:"title,omitempty"`
+	Sku          string             `json:"sku,omitempty"`
+	Status       string             `json:"status,omitempty"`
+	Barcodes     []string           `json:"barcodes,omitempty"`
+	BarcodeTypes map[string]string  `json:"barcodeTypes,omitempty"`
+	Description  *string            `json:"description,omitempty"`
+	Price        string             `json:"price,omitempty"`
+	Created      int64              `json:"created,omitempty"`
+	LastUpdated  *int64             `json:"lastUpdated,omitempty"`
+	Attributes   []ProductAttribute `json:"attributes,omitempty"`
+
+	Translations map[string]ProductTranslation `json:"translations,omitempty"`
+
+	// The locale that the title and description were picked from
+	Locale string `json:"locale,omitempty"`
+
+	/*
+		A parent product lists the attribute names its variants
+		differ by and every variant points back to its parent.
+	*/
+	ParentID    *ProductId `json:"parentId,omitempty"`
+	VariantAxes []string   `json:"variantAxes,omitempty"`
+
+	CategoryIDs []CategoryId `json:"categoryIds,omitempty"`
+	BrandID     *BrandId     `json:"brandId,omitempty"`
+
+	/*
+		Relations are never part of the default field list
+		since every related product has to be looked up as
+		well, they have to be asked for explicitly.
+	*/
+	Relations []ProductRelation `json:"relations,omitempty"`
+
+	// Only bundles have components
+	Components []BundleComponent `json:"components,omitempty"`
+
+	// Like the relations the stock has to be asked for explicitly
+	Stock []StockLevel `json:"stock,omitempty"`
+}
+
+/*
+A location is any place that holds stock, a warehouse or a
+store, and is identified by its own code like "WH-1". The
+on hand quantity is the sum of all stock movements at the
+location and is allowed to go below zero since a store can
+sell something before it has been received.
+
+What is available is whatever is on hand and not held by
+an active reservation.
+*/
+type StockLevel struct {
+	ProductID   ProductId `json:"productId"`
+	Location    string    `json:"location"`
+	OnHand      int64     `json:"onHand"`
+	Reserved    int64     `json:"reserved"`
+	Available   int64     `json:"available"`
+	LastUpdated int64     `json:"lastUpdated"`
+}
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusConfirmed = "confirmed"
+	ReservationStatusReleased  = "released"
+	ReservationStatusExpired   = "expired"
+)
+
+/*
+A reservation holds stock for a checkout until it either
+is confirmed into a sale, released or runs out of time.
+An active reservation past its expiry no longer holds any
+stock even before the sweeper has marked it as expired.
+*/
+type StockReservation struct {
+	ReservationID uint64    `json:"reservationId"`
+	ProductID     ProductId `json:"productId"`
+	Location      string    `json:"location"`
+	Quantity      int64     `json:"quantity"`
+	Status        string    `json:"status"`
+	Reference     *string   `json:"reference,omitempty"`
+	Created       int64     `json:"created"`
+	Expires       int64     `json:"expires"`
+}
+
+type StockReservationInput struct {
+	Location   string  `json:"location"`
+	Quantity   int64   `json:"quantity"`
+	TTLSeconds uint32  `json:"ttlSeconds"`
+	Reference  *string `json:"reference"`
+}
+
+const (
+	StockReasonSale     = "sale"
+	StockReasonReturn   = "return"
+	StockReasonReceipt  = "receipt"
+	StockReasonTransfer = "transfer"
+	StockReasonCount    = "count"
+)
+
+/*
+Movements are never changed or removed once they have been
+written, a mistake is corrected with another movement.
+*/
+type StockMovement struct {
+	MovementID uint64    `json:"movementId"`
+	ProductID  ProductId `json:"productId"`
+	Location   string    `json:"location"`
+	Delta      int64     `json:"delta"`
+	Reason     string    `json:"reason"`
+	Reference  *string   `json:"reference,omitempty"`
+	Created    int64     `json:"created"`
+}
+
+/*
+A transfer moves the delta from the location to the target
+location and is written as two movements sharing the same
+reference.
+*/
+type StockAdjustment struct {
+	Location   string  `json:"location"`
+	ToLocation string  `json:"toLocation"`
+	Delta      int64   `json:"delta"`
+	Reason     string  `json:"reason"`
+	Reference  *string `json:"reference"`
+}
+
+/*
+A bundle is sold as one product with its own sku and price
+but is made up of a quantity of each of its components.
+*/
+type BundleComponent struct {
+	ProductID ProductId `json:"productId"`
+	Quantity  uint32    `json:"quantity"`
+}
+
+const (
+	RelationTypeAccessoryOf = "accessory-of"
+	RelationTypeReplacedBy  = "replaced-by"
+	RelationTypeCrossSell   = "cross-sell"
+	RelationTypeUpSell      = "up-sell"
+)
+
+/*
+A relation points from the product it belongs to towards
+the related product. Relations of the same type are listed
+in their sort order.
+*/
+type ProductRelation struct {
+	Type      string    `json:"type"`
+	ProductID ProductId `json:"productId"`
+	SortOrder int32     `json:"sortOrder"`
+
+	// Only filled in when the relations are read back
+	Product *Product `json:"product,omitempty"`
+}
+
+/*
+Categories form a tree through their parent, a category
+without a parent is one of the roots. Siblings are shown
+in their sort order.
+*/
+type Category struct {
+	CategoryID CategoryId  `json:"categoryId"`
+	ParentID   *CategoryId `json:"parentId"`
+	Name       string      `json:"name"`
+	SortOrder  int32       `json:"sortOrder"`
+}
+
+/*
+The manufacturer is the company that makes the products
+which is not always the company that owns the brand.
+*/
+type Brand struct {
+	BrandID      BrandId `json:"brandId"`
+	Name         string  `json:"name"`
+	Manufacturer *string `json:"manufacturer"`
+}
+
+type BrandInput struct {
+	Name         string  `json:"name"`
+	Manufacturer *string `json:"manufacturer"`
+}
+
+// Channels are identified by a short code such as pos or web
+type Channel struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+/*
+A product is only sold in the channels it is published to.
+The title and price override the ones of the product in
+that channel when they are set.
+*/
+type ProductChannel struct {
+	Channel   string  `json:"channel"`
+	Published bool    `json:"published"`
+	Title     *string `json:"title,omitempty"`
+	Price     *string `json:"price,omitempty"`
+}
+
+type CategoryInput struct {
+	ParentID  *CategoryId `json:"parentId"`
+	Name      string      `json:"name"`
+	SortOrder int32       `json:"sortOrder"`
+}
+
+type ProductSuggestion struct {
+	ProductID ProductId `json:"productId"`
+	Title     string    `json:"title"`
+	Sku       string    `json:"sku"`
+
+	// Only needed to fill the index of every tenant on startup
+	Tenant string `json:"-"`
+}
+
+/*
+Everything that is left at its zero value falls back to a
+default picked by the service. When no symbology is given
+it is picked from the type of the barcode.
+*/
+type BarcodeImageOptions struct {
+	Format     string
+	Symbology  string
+	ModuleSize int
+	BarHeight  int
+	QuietZone  *int
 }
 
 type ProductAddInput struct {
-	Title       string             `json:"title"`
-	Sku         string             `json:"sku"`
-	Barcodes    []string           `json:"barcodes"`
-	Description *string            `json:"description"`
-	Price       *string            `json:"price"`
-	Attributes  []ProductAttribute `json:"attributes"`
+	Title           string             `json:"title"`
+	Sku             string             `json:"sku"`
+	Barcodes        []string           `json:"barcodes"`
+	GenerateBarcode bool               `json:"generateBarcode"`
+	Description     *string            `json:"description"`
+	Price           *string            `json:"price"`
+	Attributes      []ProductAttribute `json:"attributes"`
+
+	Translations map[string]ProductTranslation `json:"translations"`
+
+	ParentID    *ProductId `json:"parentId"`
+	VariantAxes []string   `json:"variantAxes"`
+
+	CategoryIDs []CategoryId `json:"categoryIds"`
+	BrandID     *BrandId     `json:"brandId"`
+
+	Components []BundleComponent `json:"components"`
+
+	// New products are active unless they are added as a draft
+	Status *string `json:"status"`
 }
 
 type ProductUpdateInput struct {
@@ -57,39 +359,449 @@ type ProductUpdateInput struct {
 	Description *string            `json:"description"`
 	Price       *string            `json:"price"`
 	Attributes  []ProductAttribute `json:"attributes"`
+
+	// Replaces all of the translations when it is set
+	Translations map[string]ProductTranslation `json:"translations"`
+
+	// Can only be changed while the product has no variants
+	VariantAxes []string `json:"variantAxes"`
+
+	// Replaces all of the categories when it is set
+	CategoryIDs []CategoryId `json:"categoryIds"`
+
+	// A brand id of zero removes the brand from the product
+	BrandID *BrandId `json:"brandId"`
+
+	// Replaces all of the components when it is set
+	Components []BundleComponent `json:"components"`
+
+	// Has to be one of the transitions allowed from the current status
+	Status *string `json:"status"`
+}
+
+/*
+The filter is shared between the product listing and the
+facet counts so that both always describe the same set
+of products.
+*/
+type ProductFilter struct {
+	Sku      string
+	Barcode  string
+	ParentID *ProductId
+
+	/*
+		The service turns the requested category into the list
+		of category ids to filter on, which includes all of
+		the subcategories when they were asked for.
+	*/
+	Category             *CategoryId
+	IncludeSubcategories bool
+	CategoryIDs          []CategoryId
+
+	BrandID *BrandId
+
+	// Products of any status are listed when it is empty
+	Statuses []string
+
+	// Only products published to the channel when it is set
+	Channel string
+
+	// Only these products when it isn't nil
+	ProductIDs []ProductId
+}
+
+type AttributeValueCount struct {
+	Value string `json:"value"`
+	Count uint32 `json:"count"`
+}
+
+type AttributeFacet struct {
+	Name   string                `json:"name"`
+	Values []AttributeValueCount `json:"values"`
+}
+
+type PriceBucketFacet struct {
+	From  string  `json:"from"`
+	To    *string `json:"to,omitempty"`
+	Count uint32  `json:"count"`
+}
+
+type ProductFacets struct {
+	Attributes []AttributeFacet   `json:"attributes"`
+	Prices     []PriceBucketFacet `json:"prices"`
+}
+
+/*
+Sizes are in millimeters and font sizes in points. The
+fields are drawn from the top of the label down in the
+order they are listed.
+*/
+type LabelTemplate struct {
+	Width         float64            `json:"width"`
+	Height        float64            `json:"height"`
+	Fields        []string           `json:"fields"`
+	FontSizes     map[string]float64 `json:"fontSizes"`
+	BarcodeHeight float64            `json:"barcodeHeight"`
+}
+
+type LabelInput struct {
+	ProductIDs []ProductId   `json:"productIds"`
+	Template   LabelTemplate `json:"template"`
+	Locale     string        `json:"locale"`
 }
 
 type ProductService interface {
+	/*
+		The locales are ordered by preference and are used
+		to pick the title, the description and the labels of
+		attribute values.
+	*/
 	GetProducts(
 		start uint64,
 		num uint64,
-		sku string,
-		barcode string,
+		filter ProductFilter,
 		fields []string,
+		locales []string,
 	) ([]Product, uint32, error)
 
-	GetProduct(id ProductId, fields []string) (*Product, error)
+	GetProductFacets(
+		filter ProductFilter,
+		priceBuckets []string,
+	) (*ProductFacets, error)
+
+	GetProduct(id ProductId, fields []string, locales []string) (*Product, error)
+
+	GetVariants(
+		id ProductId,
+		start uint64,
+		num uint64,
+		fields []string,
+		locales []string,
+	) ([]Product, uint32, error)
+
+	GetProductByBarcode(
+		barcode string,
+		normalize bool,
+		fields []string,
+		locales []string,
+	) (*Product, error)
+
+	SuggestProducts(prefix string, num uint64) ([]ProductSuggestion, error)
 	AddProduct(product ProductAddInput) (ProductId, error)
 	UpdateProduct(id ProductId, product ProductUpdateInput) error
 	DeleteProduct(id ProductId) error
+	GenerateBarcode(id ProductId) (*ProductBarcode, error)
+
+	// An empty relation type returns the relations of every type
+	GetProductRelations(
+		id ProductId,
+		relationType string,
+		locales []string,
+	) ([]ProductRelation, error)
+
+	SetProductRelations(id ProductId, relations []ProductRelation) error
+
+	GetProductChannels(id ProductId) ([]ProductChannel, error)
+
+	// Replaces every channel of the product
+	SetProductChannels(id ProductId, channels []ProductChannel) error
+
+	GetBarcodeImage(
+		id ProductId,
+		barcode string,
+		options BarcodeImageOptions,
+	) ([]byte, error)
 }
 
 type ProductRepository interface {
 	GetProducts(
 		start uint64,
 		num uint64,
-		sku string,
-		barcode string,
+		filter ProductFilter,
 		fields []string,
 	) ([]Product, uint32, error)
 
+	GetProductFacets(
+		filter ProductFilter,
+		priceBuckets []string,
+	) (*ProductFacets, error)
+
 	GetProduct(id ProductId, fields []string) (*Product, bool, error)
-	AddProduct(product ProductAddInput) (ProductId, error)
+
+	/*
+		When barcodePrefix is not empty a barcode is generated
+		from it for the new product in the same transaction.
+	*/
+	AddProduct(product ProductAddInput, barcodePrefix string) (ProductId, error)
+
 	UpdateProduct(id ProductId, product ProductUpdateInput) error
 	DeleteProduct(id ProductId) error
+	GenerateBarcode(id ProductId, barcodePrefix string) (string, error)
 	GetBarcodes(barcodes []string) ([]ProductBarcode, error)
 	GetSku(sku string) (*ProductSku, error)
 	ProductExists(id ProductId) (bool, error)
+	GetSuggestions() ([]ProductSuggestion, error)
+	GetVariantAttributes(parentID ProductId) (map[ProductId][]ProductAttribute, error)
+	CountVariants(parentID ProductId) (uint32, error)
+	GetBundleComponents(ids []ProductId) (map[ProductId][]BundleComponent, error)
+	CountBundlesContaining(id ProductId) (uint32, error)
+	GetRelations(ids []ProductId) (map[ProductId][]ProductRelation, error)
+	SetRelations(id ProductId, relations []ProductRelation) error
+	GetChannels(id ProductId) ([]ProductChannel, error)
+	SetChannels(id ProductId, channels []ProductChannel) error
+
+	// Only the products that are published to the channel are returned
+	GetChannelOverrides(channel string, ids []ProductId) (map[ProductId]ProductChannel, error)
+}
+
+/*
+The suggestion index lives in memory for the whole lifetime
+of the process and is kept up to date by the service
+whenever a product is written.
+*/
+type ProductSuggestionIndex interface {
+	Put(suggestion ProductSuggestion)
+	Remove(id ProductId)
+	Find(prefix string, num int) []ProductSuggestion
+}
+
+type LabelService interface {
+	GetLabelsPDF(input LabelInput) ([]byte, error)
+}
+
+type AttributeService interface {
+	GetAttributeDefinitions() ([]AttributeDefinition, error)
+	GetAttributeDefinition(name string) (*AttributeDefinition, error)
+	AddAttributeDefinition(definition AttributeDefinition) error
+	UpdateAttributeDefinition(name string, definition AttributeDefinition) error
+	DeleteAttributeDefinition(name string) error
+}
+
+type AttributeRepository interface {
+	GetAttributeDefinitions() ([]AttributeDefinition, error)
+	GetAttributeDefinition(name string) (*AttributeDefinition, bool, error)
+	AddAttributeDefinition(definition AttributeDefinition) error
+	UpdateAttributeDefinition(name string, definition AttributeDefinition) error
+	DeleteAttributeDefinition(name string) error
+}
+
+type CategoryService interface {
+	GetCategories() ([]Category, error)
+	GetCategory(id CategoryId) (*Category, error)
+	AddCategory(category CategoryInput) (CategoryId, error)
+	UpdateCategory(id CategoryId, category CategoryInput) error
+	DeleteCategory(id CategoryId) error
+}
+
+type CategoryRepository interface {
+	GetCategories() ([]Category, error)
+	GetCategory(id CategoryId) (*Category, bool, error)
+	AddCategory(category CategoryInput) (CategoryId, error)
+	UpdateCategory(id CategoryId, category CategoryInput) error
+	DeleteCategory(id CategoryId) error
+}
+
+type BrandService interface {
+	GetBrands() ([]Brand, error)
+	GetBrand(id BrandId) (*Brand, error)
+	AddBrand(brand BrandInput) (BrandId, error)
+	UpdateBrand(id BrandId, brand BrandInput) error
+	DeleteBrand(id BrandId) error
+}
+
+type BrandRepository interface {
+	GetBrands() ([]Brand, error)
+	GetBrand(id BrandId) (*Brand, bool, error)
+	AddBrand(brand BrandInput) (BrandId, error)
+	UpdateBrand(id BrandId, brand BrandInput) error
+	DeleteBrand(id BrandId) error
+	CountBrandProducts(id BrandId) (uint32, error)
+}
+
+type ChannelService interface {
+	GetChannels() ([]Channel, error)
+	GetChannel(code string) (*Channel, error)
+	AddChannel(channel Channel) error
+	UpdateChannel(code string, channel Channel) error
+	DeleteChannel(code string) error
+}
+
+type ChannelRepository interface {
+	GetChannels() ([]Channel, error)
+	GetChannel(code string) (*Channel, bool, error)
+	AddChannel(channel Channel) error
+	UpdateChannel(code string, channel Channel) error
+	DeleteChannel(code string) error
+	CountChannelProducts(code string) (uint32, error)
+}
+
+type StockService interface {
+	GetStock(id ProductId) ([]StockLevel, error)
+
+	// Returns the new stock level of every location that changed
+	AdjustStock(id ProductId, adjustment StockAdjustment) ([]StockLevel, error)
+
+	// An empty location returns the movements of every location
+	GetStockMovements(
+		id ProductId,
+		location string,
+		start uint64,
+		num uint64,
+	) ([]StockMovement, uint32, error)
+
+	ReserveStock(id ProductId, reservation StockReservationInput) (*StockReservation, error)
+	GetReservation(id uint64) (*StockReservation, error)
+	ConfirmReservation(id uint64) (*StockReservation, error)
+	ReleaseReservation(id uint64) (*StockReservation, error)
+
+	// Called by the sweeper in the background
+	ExpireReservations() error
+}
+
+type StockRepository interface {
+	GetStockLevels(id ProductId) ([]StockLevel, error)
+	AddMovements(id ProductId, movements []StockMovement) ([]StockLevel, error)
+
+	GetMovements(
+		id ProductId,
+		location string,
+		start uint64,
+		num uint64,
+	) ([]StockMovement, uint32, error)
+
+	ReserveStock(
+		id ProductId,
+		reservation StockReservationInput,
+		expires time.Time,
+	) (*StockReservation, error)
+
+	GetReservation(id uint64) (*StockReservation, bool, error)
+
+	// Writes the sale movement and marks the reservation confirmed
+	ConfirmReservation(id uint64) (*StockReservation, error)
+
+	ReleaseReservation(id uint64) (*StockReservation, error)
+	ExpireReservations(now time.Time) (int64, error)
+}
+
+const (
+	StocktakeStatusOpen      = "open"
+	StocktakeStatusCommitted = "committed"
+)
+
+/*
+A stocktake counts the stock at one location. Scans add to
+the count of the product the barcode belongs to and nothing
+changes in the stock until the stocktake is committed.
+*/
+type Stocktake struct {
+	StocktakeID uint64           `json:"stocktakeId"`
+	Location    string           `json:"location"`
+	Status      string           `json:"status"`
+	Created     int64            `json:"created"`
+	Committed   *int64           `json:"committed,omitempty"`
+	Counts      []StocktakeCount `json:"counts"`
+}
+
+type StocktakeCount struct {
+	ProductID ProductId `json:"productId"`
+	Counted   int64     `json:"counted"`
+}
+
+type StocktakeScan struct {
+	Barcode  string `json:"barcode"`
+	Quantity int64  `json:"quantity"`
+}
+
+// Only the products that were counted are compared
+type StocktakeDiscrepancy struct {
+	ProductID  ProductId `json:"productId"`
+	Expected   int64     `json:"expected"`
+	Counted    int64     `json:"counted"`
+	Difference int64     `json:"difference"`
+}
+
+type StocktakeService interface {
+	OpenStocktake(location string) (uint64, error)
+	GetStocktake(id uint64) (*Stocktake, error)
+	AddStocktakeScans(id uint64, scans []StocktakeScan) (*Stocktake, error)
+	GetStocktakeDiscrepancies(id uint64) ([]StocktakeDiscrepancy, error)
+
+	// Returns the discrepancies that were turned into movements
+	CommitStocktake(id uint64) ([]StocktakeDiscrepancy, error)
+}
+
+type StocktakeRepository interface {
+	AddStocktake(location string) (uint64, error)
+	GetStocktake(id uint64) (*Stocktake, bool, error)
+	AddCounts(id uint64, counts []StocktakeCount) error
+	GetDiscrepancies(id uint64) ([]StocktakeDiscrepancy, error)
+	CommitStocktake(id uint64) ([]StocktakeDiscrepancy, error)
+}
+
+/*
+Viewers can only read, editors can change products except
+for their price which takes a price manager. Admins can do
+everything, including deleting products.
+*/
+const (
+	RoleViewer       = "viewer"
+	RoleEditor       = "editor"
+	RolePriceManager = "price-manager"
+	RoleAdmin        = "admin"
+)
+
+/*
+Only a hash of the key is stored, the key itself is sent
+back once when it is created. The prefix is kept to tell
+keys apart in the listing.
+*/
+type ApiKey struct {
+	KeyID   uint64   `json:"keyId"`
+	Name    string   `json:"name"`
+	Prefix  string   `json:"prefix"`
+	Roles   []string `json:"roles"`
+	Created int64    `json:"created"`
+	Revoked *int64   `json:"revoked,omitempty"`
+}
+
+// A key without any roles is a viewer
+type ApiKeyInput struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type CreatedApiKey struct {
+	ApiKey
+	Key string `json:"key"`
+}
+
+type ApiKeyService interface {
+	GetApiKeys() ([]ApiKey, error)
+	CreateApiKey(input ApiKeyInput) (*CreatedApiKey, error)
+	RevokeApiKey(id uint64) error
+
+	// Returns the identity and roles of the caller to record in the metadata
+	Authenticate(key string) (string, []string, error)
+}
+
+type ApiKeyRepository interface {
+	GetApiKeys() ([]ApiKey, error)
+	GetApiKey(id uint64) (*ApiKey, bool, error)
+	GetApiKeyByHash(hash string) (*ApiKey, bool, error)
+	AddApiKey(input ApiKeyInput, prefix string, hash string) (uint64, error)
+	RevokeApiKey(id uint64) error
+}
+
+// What a verified bearer token says about the caller
+type TokenClaims struct {
+	Subject string
+	Tenant  string
+	Roles   []string
+}
+
+type TokenService interface {
+	VerifyToken(token string) (*TokenClaims, error)
 }
 
 type ProductServer interface {
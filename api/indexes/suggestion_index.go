@@ -0,0 +1,156 @@
+package indexes
+
+import (
+	"api/domain"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+The suggestion index keeps every product title and sku in
+memory sorted by key. Looking up a prefix is then just a
+binary search followed by a short scan which is a lot
+cheaper than a LIKE query against the database.
+
+Titles are indexed both as a whole and from the start of
+every word so that "shirt" finds "Blue shirt".
+
+The index is shared between all requests so every access
+goes through the mutex.
+*/
+type SuggestionIndex struct {
+	mutex    sync.RWMutex
+	entries  []suggestionEntry
+	products map[domain.ProductId]domain.ProductSuggestion
+}
+
+type suggestionEntry struct {
+	key       string
+	productID domain.ProductId
+}
+
+func NewSuggestionIndex() *SuggestionIndex {
+	return &SuggestionIndex{
+		products: map[domain.ProductId]domain.ProductSuggestion{},
+	}
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+func getSuggestionKeys(suggestion domain.ProductSuggestion) []string {
+	keys := []string{normalizeKey(suggestion.Sku)}
+
+	title := normalizeKey(suggestion.Title)
+	words := strings.Fields(title)
+
+	for i := range words {
+		keys = append(keys, strings.Join(words[i:], " "))
+	}
+
+	return keys
+}
+
+func (index *SuggestionIndex) search(key string) int {
+	return sort.Search(len(index.entries), func(i int) bool {
+		return index.entries[i].key >= key
+	})
+}
+
+func (index *SuggestionIndex) insert(entry suggestionEntry) {
+	position := index.search(entry.key)
+
+	index.entries = append(index.entries, suggestionEntry{})
+	copy(index.entries[position+1:], index.entries[position:])
+	index.entries[position] = entry
+}
+
+func (index *SuggestionIndex) remove(id domain.ProductId) {
+	existing, exists := index.products[id]
+
+	if !exists {
+		return
+	}
+
+	for _, key := range getSuggestionKeys(existing) {
+		for i := index.search(key); i < len(index.entries) && index.entries[i].key == key; i++ {
+			if index.entries[i].productID == id {
+				index.entries = append(index.entries[:i], index.entries[i+1:]...)
+				break
+			}
+		}
+	}
+
+	delete(index.products, id)
+}
+
+/*
+Put adds a product to the index or replaces whatever was
+indexed for it before.
+*/
+func (index *SuggestionIndex) Put(suggestion domain.ProductSuggestion) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.remove(suggestion.ProductID)
+
+	for _, key := range getSuggestionKeys(suggestion) {
+		if key == "" {
+			continue
+		}
+
+		index.insert(suggestionEntry{
+			key:       key,
+			productID: suggestion.ProductID,
+		})
+	}
+
+	index.products[suggestion.ProductID] = suggestion
+}
+
+func (index *SuggestionIndex) Remove(id domain.ProductId) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.remove(id)
+}
+
+/*
+Find returns at most num products that have a sku or title
+word starting with the prefix. Keys that sort first are
+returned first and every product is returned only once.
+*/
+func (index *SuggestionIndex) Find(
+	prefix string,
+	num int,
+) []domain.ProductSuggestion {
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	prefix = normalizeKey(prefix)
+
+	suggestions := []domain.ProductSuggestion{}
+	seen := map[domain.ProductId]struct{}{}
+
+	for i := index.search(prefix); i < len(index.entries) && len(suggestions) < num; i++ {
+		entry := index.entries[i]
+
+		if !strings.HasPrefix(entry.key, prefix) {
+			break
+		}
+
+		_, alreadySeen := seen[entry.productID]
+
+		if alreadySeen {
+			continue
+		}
+
+		seen[entry.productID] = struct{}{}
+		suggestions = append(suggestions, index.products[entry.productID])
+	}
+
+	return suggestions
+}
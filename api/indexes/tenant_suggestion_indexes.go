@@ -0,0 +1,35 @@
+package indexes
+
+import (
+	"sync"
+)
+
+/*
+Every tenant gets a suggestion index of its own so that a
+prefix never turns up products from another catalogue. The
+index of a tenant is created the first time it's asked for.
+*/
+type TenantSuggestionIndexes struct {
+	mutex   sync.Mutex
+	indexes map[string]*SuggestionIndex
+}
+
+func NewTenantSuggestionIndexes() *TenantSuggestionIndexes {
+	return &TenantSuggestionIndexes{
+		indexes: map[string]*SuggestionIndex{},
+	}
+}
+
+func (tenants *TenantSuggestionIndexes) ForTenant(tenant string) *SuggestionIndex {
+	tenants.mutex.Lock()
+	defer tenants.mutex.Unlock()
+
+	index, exists := tenants.indexes[tenant]
+
+	if !exists {
+		index = NewSuggestionIndex()
+		tenants.indexes[tenant] = index
+	}
+
+	return index
+}
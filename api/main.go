@@ -1,6 +1,7 @@
 package main
 
 import (
+	"api/indexes"
 	"api/repositories"
 	"api/servers"
 	"api/services"
@@ -59,24 +60,202 @@ func main() {
 		}
 	}
 
+	config := util.LoadConfig()
+
+	suggestions := indexes.NewTenantSuggestionIndexes()
+
+	initialSuggestions, err := repositories.ProductRepositoryImpl{
+		DB: connection,
+	}.GetSuggestions()
+
+	if err != nil {
+		log.Fatalf("Could not load product suggestions with error %s", err.Error())
+	}
+
+	for _, suggestion := range initialSuggestions {
+		suggestions.ForTenant(suggestion.Tenant).Put(suggestion)
+	}
+
+	log.Printf("Loaded %v product suggestions", len(initialSuggestions))
+
+	var keySet *util.KeySet
+
+	if config.JWKSFile != "" || config.JWKSURL != "" {
+		// Without them any token signed by the keys would do
+		if config.JWTIssuer == "" || config.JWTAudience == "" {
+			log.Fatal("JWT_ISSUER and JWT_AUDIENCE have to be set together with the JWKS")
+		}
+
+		keySet, err = util.LoadKeySet(config.JWKSFile, config.JWKSURL)
+
+		if err != nil {
+			log.Fatalf("Could not load the JWKS with error %s", err.Error())
+		}
+
+		log.Println("Loaded JWKS, bearer tokens are accepted")
+	}
+
+	/*
+		The sweeper runs for the whole lifetime of the process
+		and uses its own service since it is not part of any
+		request.
+	*/
+	go func() {
+		sweeper := services.StockServiceImpl{
+			Repo: repositories.StockRepositoryImpl{
+				DB: connection,
+			},
+			Products: repositories.ProductRepositoryImpl{
+				DB: connection,
+			},
+		}
+
+		for range time.Tick(config.ReservationSweepInterval) {
+			sweeper.ExpireReservations()
+		}
+	}()
+
 	var requestId uint32
 
 	http.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
 		requestId++
 
+		tenant, err := servers.ResolveTenant(request)
+
+		if err != nil {
+			servers.WriteBadRequest(writer, err.Error())
+			return
+		}
+
+		metadata := util.Metadata{
+			RequestID: requestId,
+			Tenant:    tenant,
+		}
+
+		apiKeyService := services.ApiKeyServiceImpl{
+			Repo: repositories.ApiKeyRepositoryImpl{
+				DB:       connection,
+				Metadata: metadata,
+			},
+			Config:   config,
+			Metadata: metadata,
+		}
+
+		tokenService := services.TokenServiceImpl{
+			Keys:     keySet,
+			Config:   config,
+			Metadata: metadata,
+		}
+
+		metadata, err = servers.Authenticate(request, metadata, apiKeyService, tokenService)
+
+		if err != nil {
+			servers.WriteServiceError(writer, err)
+			return
+		}
+
+		apiKeyService = services.ApiKeyServiceImpl{
+			Repo: repositories.ApiKeyRepositoryImpl{
+				DB:       connection,
+				Metadata: metadata,
+			},
+			Config:   config,
+			Metadata: metadata,
+		}
+
 		repo := repositories.ProductRepositoryImpl{
-			DB: connection,
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		attributeRepo := repositories.AttributeRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		categoryRepo := repositories.CategoryRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		brandRepo := repositories.BrandRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		channelRepo := repositories.ChannelRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		stockRepo := repositories.StockRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
+		}
+
+		stocktakeRepo := repositories.StocktakeRepositoryImpl{
+			DB:       connection,
+			Metadata: metadata,
 		}
 
 		service := services.ProductServiceImpl{
-			Repo: repo,
-			Metadata: util.Metadata{
-				RequestID: requestId,
-			},
+			Repo:          repo,
+			AttributeRepo: attributeRepo,
+			CategoryRepo:  categoryRepo,
+			BrandRepo:     brandRepo,
+			ChannelRepo:   channelRepo,
+			Suggestions:   suggestions.ForTenant(metadata.Tenant),
+			Config:        config,
+			Metadata:      metadata,
+		}
+
+		labelService := services.LabelServiceImpl{
+			Products: service,
+			Metadata: metadata,
+		}
+
+		attributeService := services.AttributeServiceImpl{
+			Repo:     attributeRepo,
+			Metadata: metadata,
+		}
+
+		categoryService := services.CategoryServiceImpl{
+			Repo:     categoryRepo,
+			Metadata: metadata,
+		}
+
+		brandService := services.BrandServiceImpl{
+			Repo:     brandRepo,
+			Metadata: metadata,
+		}
+
+		channelService := services.ChannelServiceImpl{
+			Repo:     channelRepo,
+			Metadata: metadata,
+		}
+
+		stockService := services.StockServiceImpl{
+			Repo:     stockRepo,
+			Products: repo,
+			Metadata: metadata,
+		}
+
+		stocktakeService := services.StocktakeServiceImpl{
+			Repo:     stocktakeRepo,
+			Products: repo,
+			Metadata: metadata,
 		}
 
 		server := servers.Server{
-			Service: service,
+			Service:          service,
+			LabelService:     labelService,
+			AttributeService: attributeService,
+			CategoryService:  categoryService,
+			BrandService:     brandService,
+			ChannelService:   channelService,
+			ApiKeyService:    apiKeyService,
+			StockService:     stockService,
+			StocktakeService: stocktakeService,
 		}
 
 		server.HandleRequest(writer, request)
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Keys belong to the tenant in the metadata and only work for it
+type ApiKeyRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func (repo ApiKeyRepositoryImpl) getApiKeys(
+	predicate interface{},
+) ([]domain.ApiKey, error) {
+
+	query := sq.Select("key_id", "name", "prefix", "roles", "created", "revoked").
+		From("api_key").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		OrderBy("key_id")
+
+	if predicate != nil {
+		query = query.Where(predicate)
+	}
+
+	rows, err := query.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := []domain.ApiKey{}
+
+	for rows.Next() {
+		key := domain.ApiKey{}
+		var roles string
+		var created string
+		var revoked *string
+
+		err := rows.Scan(&key.KeyID, &key.Name, &key.Prefix, &roles, &created, &revoked)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key.Roles = []string{}
+
+		if roles != "" {
+			key.Roles = strings.Split(roles, ",")
+		}
+
+		key.Created, err = convertSQLDateToTimestamp(created)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked != nil {
+			revokedTimestamp, err := convertSQLDateToTimestamp(*revoked)
+
+			if err != nil {
+				return nil, err
+			}
+
+			key.Revoked = &revokedTimestamp
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (repo ApiKeyRepositoryImpl) GetApiKeys() ([]domain.ApiKey, error) {
+	return repo.getApiKeys(nil)
+}
+
+func (repo ApiKeyRepositoryImpl) getApiKey(
+	predicate interface{},
+) (*domain.ApiKey, bool, error) {
+
+	keys, err := repo.getApiKeys(predicate)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(keys) == 0 {
+		return nil, false, nil
+	}
+
+	return &keys[0], true, nil
+}
+
+func (repo ApiKeyRepositoryImpl) GetApiKey(
+	id uint64,
+) (*domain.ApiKey, bool, error) {
+
+	return repo.getApiKey(sq.Eq{
+		"key_id": id,
+	})
+}
+
+func (repo ApiKeyRepositoryImpl) GetApiKeyByHash(
+	hash string,
+) (*domain.ApiKey, bool, error) {
+
+	return repo.getApiKey(sq.Eq{
+		"key_hash": hash,
+	})
+}
+
+// The roles are stored as a comma separated list
+func (repo ApiKeyRepositoryImpl) AddApiKey(
+	input domain.ApiKeyInput,
+	prefix string,
+	hash string,
+) (uint64, error) {
+
+	res, err := sq.Insert("api_key").
+		Columns("tenant", "name", "prefix", "roles", "key_hash", "created").
+		Values(
+			repo.Metadata.Tenant,
+			input.Name,
+			prefix,
+			strings.Join(input.Roles, ","),
+			hash,
+			time.Now(),
+		).
+		RunWith(repo.DB).
+		Exec()
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+
+	return uint64(id), err
+}
+
+// Revoked keys are kept so the listing still shows them
+func (repo ApiKeyRepositoryImpl) RevokeApiKey(
+	id uint64,
+) error {
+
+	_, err := sq.Update("api_key").
+		Set("revoked", time.Now()).
+		Where(sq.Eq{
+			"key_id":  id,
+			"tenant":  repo.Metadata.Tenant,
+			"revoked": nil,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
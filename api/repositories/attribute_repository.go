@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Attribute definitions are scoped to the tenant in the metadata
+type AttributeRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func insertAllowedValues(
+	tx *sql.Tx,
+	tenant string,
+	definition domain.AttributeDefinition,
+) error {
+
+	if len(definition.AllowedValues) == 0 {
+		return nil
+	}
+
+	valueInsert := sq.Insert("attribute_definition_value").
+		Columns("tenant", "name", "value", "sort_order")
+
+	for i, value := range definition.AllowedValues {
+		valueInsert = valueInsert.Values(tenant, definition.Name, value, i)
+	}
+
+	_, err := valueInsert.RunWith(tx).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	if len(definition.ValueLabels) == 0 {
+		return nil
+	}
+
+	labelInsert := sq.Insert("attribute_definition_value_label").
+		Columns("tenant", "name", "value", "locale", "label")
+
+	for value, labels := range definition.ValueLabels {
+		for locale, label := range labels {
+			labelInsert = labelInsert.Values(tenant, definition.Name, value, locale, label)
+		}
+	}
+
+	_, err = labelInsert.RunWith(tx).Exec()
+
+	return err
+}
+
+func deleteAllowedValues(
+	tx *sql.Tx,
+	predicate sq.Eq,
+) error {
+
+	_, err := sq.Delete("attribute_definition_value").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Delete("attribute_definition_value_label").Where(predicate).RunWith(tx).Exec()
+
+	return err
+}
+
+/*
+Reads the definitions matching the predicate together with
+their allowed values, in the order they were given in, and
+the labels of those values.
+*/
+func (repo AttributeRepositoryImpl) getDefinitions(
+	predicate interface{},
+) ([]domain.AttributeDefinition, error) {
+
+	query := sq.Select("name", "data_type", "unit", "required").
+		From("attribute_definition").
+		OrderBy("name")
+
+	valueQuery := sq.Select("name", "value").
+		From("attribute_definition_value").
+		OrderBy("name", "sort_order")
+
+	labelQuery := sq.Select("name", "value", "locale", "label").
+		From("attribute_definition_value_label")
+
+	tenantPredicate := sq.Eq{
+		"tenant": repo.Metadata.Tenant,
+	}
+
+	query = query.Where(tenantPredicate)
+	valueQuery = valueQuery.Where(tenantPredicate)
+	labelQuery = labelQuery.Where(tenantPredicate)
+
+	if predicate != nil {
+		query = query.Where(predicate)
+		valueQuery = valueQuery.Where(predicate)
+		labelQuery = labelQuery.Where(predicate)
+	}
+
+	rows, err := query.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	definitions := []domain.AttributeDefinition{}
+	definitionIndexes := map[string]int{}
+
+	for rows.Next() {
+		definition := domain.AttributeDefinition{}
+
+		err := rows.Scan(
+			&definition.Name,
+			&definition.DataType,
+			&definition.Unit,
+			&definition.Required,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		definitionIndexes[definition.Name] = len(definitions)
+		definitions = append(definitions, definition)
+	}
+
+	valueRows, err := valueQuery.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer valueRows.Close()
+
+	for valueRows.Next() {
+		var name string
+		var value string
+
+		err := valueRows.Scan(&name, &value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		index, exists := definitionIndexes[name]
+
+		if exists {
+			definitions[index].AllowedValues = append(definitions[index].AllowedValues, value)
+		}
+	}
+
+	labelRows, err := labelQuery.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer labelRows.Close()
+
+	for labelRows.Next() {
+		var name string
+		var value string
+		var locale string
+		var label string
+
+		err := labelRows.Scan(&name, &value, &locale, &label)
+
+		if err != nil {
+			return nil, err
+		}
+
+		index, exists := definitionIndexes[name]
+
+		if !exists {
+			continue
+		}
+
+		definition := &definitions[index]
+
+		if definition.ValueLabels == nil {
+			definition.ValueLabels = map[string]map[string]string{}
+		}
+
+		if definition.ValueLabels[value] == nil {
+			definition.ValueLabels[value] = map[string]string{}
+		}
+
+		definition.ValueLabels[value][locale] = label
+	}
+
+	return definitions, nil
+}
+
+func (repo AttributeRepositoryImpl) GetAttributeDefinitions() ([]domain.AttributeDefinition, error) {
+	return repo.getDefinitions(nil)
+}
+
+func (repo AttributeRepositoryImpl) GetAttributeDefinition(
+	name string,
+) (*domain.AttributeDefinition, bool, error) {
+
+	definitions, err := repo.getDefinitions(sq.Eq{
+		"name": name,
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(definitions) == 0 {
+		return nil, false, nil
+	}
+
+	return &definitions[0], true, nil
+}
+
+func (repo AttributeRepositoryImpl) AddAttributeDefinition(
+	definition domain.AttributeDefinition,
+) error {
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Insert("attribute_definition").
+		Columns("tenant", "name", "data_type", "unit", "required").
+		Values(
+			repo.Metadata.Tenant,
+			definition.Name,
+			definition.DataType,
+			definition.Unit,
+			definition.Required,
+		).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = insertAllowedValues(tx, repo.Metadata.Tenant, definition)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo AttributeRepositoryImpl) UpdateAttributeDefinition(
+	name string,
+	definition domain.AttributeDefinition,
+) error {
+
+	predicate := sq.Eq{
+		"tenant": repo.Metadata.Tenant,
+		"name":   name,
+	}
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Update("attribute_definition").
+		Set("data_type", definition.DataType).
+		Set("unit", definition.Unit).
+		Set("required", definition.Required).
+		Where(predicate).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = deleteAllowedValues(tx, predicate)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	definition.Name = name
+
+	err = insertAllowedValues(tx, repo.Metadata.Tenant, definition)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo AttributeRepositoryImpl) DeleteAttributeDefinition(
+	name string,
+) error {
+
+	predicate := sq.Eq{
+		"tenant": repo.Metadata.Tenant,
+		"name":   name,
+	}
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Delete("attribute_definition").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = deleteAllowedValues(tx, predicate)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Brands are scoped to the tenant in the metadata
+type BrandRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func (repo BrandRepositoryImpl) getBrands(
+	predicate interface{},
+) ([]domain.Brand, error) {
+
+	query := sq.Select("brand_id", "name", "manufacturer").
+		From("brand").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		OrderBy("name")
+
+	if predicate != nil {
+		query = query.Where(predicate)
+	}
+
+	rows, err := query.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	brands := []domain.Brand{}
+
+	for rows.Next() {
+		brand := domain.Brand{}
+
+		err := rows.Scan(&brand.BrandID, &brand.Name, &brand.Manufacturer)
+
+		if err != nil {
+			return nil, err
+		}
+
+		brands = append(brands, brand)
+	}
+
+	return brands, nil
+}
+
+func (repo BrandRepositoryImpl) GetBrands() ([]domain.Brand, error) {
+	return repo.getBrands(nil)
+}
+
+func (repo BrandRepositoryImpl) GetBrand(
+	id domain.BrandId,
+) (*domain.Brand, bool, error) {
+
+	brands, err := repo.getBrands(sq.Eq{
+		"brand_id": id,
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(brands) == 0 {
+		return nil, false, nil
+	}
+
+	return &brands[0], true, nil
+}
+
+func (repo BrandRepositoryImpl) AddBrand(
+	brand domain.BrandInput,
+) (domain.BrandId, error) {
+
+	res, err := sq.Insert("brand").
+		Columns("tenant", "name", "manufacturer").
+		Values(repo.Metadata.Tenant, brand.Name, brand.Manufacturer).
+		RunWith(repo.DB).
+		Exec()
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return domain.BrandId(id), nil
+}
+
+func (repo BrandRepositoryImpl) UpdateBrand(
+	id domain.BrandId,
+	brand domain.BrandInput,
+) error {
+
+	_, err := sq.Update("brand").
+		Set("name", brand.Name).
+		Set("manufacturer", brand.Manufacturer).
+		Where(sq.Eq{
+			"tenant":   repo.Metadata.Tenant,
+			"brand_id": id,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+func (repo BrandRepositoryImpl) DeleteBrand(
+	id domain.BrandId,
+) error {
+
+	_, err := sq.Delete("brand").
+		Where(sq.Eq{
+			"tenant":   repo.Metadata.Tenant,
+			"brand_id": id,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+func (repo BrandRepositoryImpl) CountBrandProducts(
+	id domain.BrandId,
+) (uint32, error) {
+
+	var count uint32
+
+	err := sq.Select("count(*)").
+		From("product").
+		Where(sq.Eq{
+			"tenant":   repo.Metadata.Tenant,
+			"brand_id": id,
+		}).
+		RunWith(repo.DB).
+		QueryRow().
+		Scan(&count)
+
+	return count, err
+}
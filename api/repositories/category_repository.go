@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Categories are scoped to the tenant in the metadata
+type CategoryRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func (repo CategoryRepositoryImpl) getCategories(
+	predicate interface{},
+) ([]domain.Category, error) {
+
+	query := sq.Select("category_id", "parent_id", "name", "sort_order").
+		From("category").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		OrderBy("parent_id", "sort_order", "name")
+
+	if predicate != nil {
+		query = query.Where(predicate)
+	}
+
+	rows, err := query.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	categories := []domain.Category{}
+
+	for rows.Next() {
+		category := domain.Category{}
+
+		err := rows.Scan(
+			&category.CategoryID,
+			&category.ParentID,
+			&category.Name,
+			&category.SortOrder,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+func (repo CategoryRepositoryImpl) GetCategories() ([]domain.Category, error) {
+	return repo.getCategories(nil)
+}
+
+func (repo CategoryRepositoryImpl) GetCategory(
+	id domain.CategoryId,
+) (*domain.Category, bool, error) {
+
+	categories, err := repo.getCategories(sq.Eq{
+		"category_id": id,
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(categories) == 0 {
+		return nil, false, nil
+	}
+
+	return &categories[0], true, nil
+}
+
+func (repo CategoryRepositoryImpl) AddCategory(
+	category domain.CategoryInput,
+) (domain.CategoryId, error) {
+
+	res, err := sq.Insert("category").
+		Columns("tenant", "parent_id", "name", "sort_order").
+		Values(repo.Metadata.Tenant, category.ParentID, category.Name, category.SortOrder).
+		RunWith(repo.DB).
+		Exec()
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return domain.CategoryId(id), nil
+}
+
+func (repo CategoryRepositoryImpl) UpdateCategory(
+	id domain.CategoryId,
+	category domain.CategoryInput,
+) error {
+
+	_, err := sq.Update("category").
+		Set("parent_id", category.ParentID).
+		Set("name", category.Name).
+		Set("sort_order", category.SortOrder).
+		Where(sq.Eq{
+			"tenant":      repo.Metadata.Tenant,
+			"category_id": id,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+/*
+Products are only unlinked from the category, the products
+themselves are left alone. Category ids are unique across
+tenants so the links are only removed once the category
+itself is gone.
+*/
+func (repo CategoryRepositoryImpl) DeleteCategory(
+	id domain.CategoryId,
+) error {
+
+	predicate := sq.Eq{
+		"category_id": id,
+	}
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := sq.Delete("category").
+		Where(predicate).
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deleted, err := res.RowsAffected()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if deleted == 0 {
+		return tx.Commit()
+	}
+
+	_, err = sq.Delete("product_category").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Channels are scoped to the tenant in the metadata
+type ChannelRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func (repo ChannelRepositoryImpl) getChannels(
+	predicate interface{},
+) ([]domain.Channel, error) {
+
+	query := sq.Select("code", "name").
+		From("channel").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		OrderBy("code")
+
+	if predicate != nil {
+		query = query.Where(predicate)
+	}
+
+	rows, err := query.RunWith(repo.DB).Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	channels := []domain.Channel{}
+
+	for rows.Next() {
+		channel := domain.Channel{}
+
+		err := rows.Scan(&channel.Code, &channel.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+func (repo ChannelRepositoryImpl) GetChannels() ([]domain.Channel, error) {
+	return repo.getChannels(nil)
+}
+
+func (repo ChannelRepositoryImpl) GetChannel(
+	code string,
+) (*domain.Channel, bool, error) {
+
+	channels, err := repo.getChannels(sq.Eq{
+		"code": code,
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(channels) == 0 {
+		return nil, false, nil
+	}
+
+	return &channels[0], true, nil
+}
+
+func (repo ChannelRepositoryImpl) AddChannel(
+	channel domain.Channel,
+) error {
+
+	_, err := sq.Insert("channel").
+		Columns("tenant", "code", "name").
+		Values(repo.Metadata.Tenant, channel.Code, channel.Name).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+// The code can't be changed since products refer to it
+func (repo ChannelRepositoryImpl) UpdateChannel(
+	code string,
+	channel domain.Channel,
+) error {
+
+	_, err := sq.Update("channel").
+		Set("name", channel.Name).
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+			"code":   code,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+func (repo ChannelRepositoryImpl) DeleteChannel(
+	code string,
+) error {
+
+	_, err := sq.Delete("channel").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+			"code":   code,
+		}).
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+/*
+Product channels don't have a tenant of their own, the product
+they belong to decides it.
+*/
+func (repo ChannelRepositoryImpl) CountChannelProducts(
+	code string,
+) (uint32, error) {
+
+	var count uint32
+
+	productSql, productArgs, err := sq.Select("product_id").
+		From("product").
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = sq.Select("count(*)").
+		From("product_channel").
+		Where(sq.Eq{
+			"channel": code,
+		}).
+		Where("product_id IN ("+productSql+")", productArgs...).
+		RunWith(repo.DB).
+		QueryRow().
+		Scan(&count)
+
+	return count, err
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"api/domain"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func insertBundleComponents(
+	tx *sql.Tx,
+	id domain.ProductId,
+	components []domain.BundleComponent,
+) error {
+
+	if len(components) == 0 {
+		return nil
+	}
+
+	componentInsert := sq.Insert("product_bundle_component").
+		Columns("product_id", "component_product_id", "quantity")
+
+	for _, component := range components {
+		componentInsert = componentInsert.Values(id, component.ProductID, component.Quantity)
+	}
+
+	_, err := componentInsert.RunWith(tx).Exec()
+
+	return err
+}
+
+/*
+Reads the components of every bundle matching the predicate
+grouped by the bundle they belong to.
+*/
+func (repo ProductRepositoryImpl) getBundleComponents(
+	predicate interface{},
+) (map[domain.ProductId][]domain.BundleComponent, error) {
+
+	rows, err := sq.Select("product_id", "component_product_id", "quantity").
+		From("product_bundle_component").
+		Where(predicate).
+		OrderBy("product_id", "component_product_id").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	components := map[domain.ProductId][]domain.BundleComponent{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		component := domain.BundleComponent{}
+
+		err := rows.Scan(&productID, &component.ProductID, &component.Quantity)
+
+		if err != nil {
+			return nil, err
+		}
+
+		components[productID] = append(components[productID], component)
+	}
+
+	return components, nil
+}
+
+func (repo ProductRepositoryImpl) GetBundleComponents(
+	ids []domain.ProductId,
+) (map[domain.ProductId][]domain.BundleComponent, error) {
+
+	return repo.getBundleComponents(sq.Eq{
+		"product_id": ids,
+	})
+}
+
+func (repo ProductRepositoryImpl) CountBundlesContaining(
+	id domain.ProductId,
+) (uint32, error) {
+
+	var count uint32
+
+	err := sq.Select("count(*)").
+		From("product_bundle_component").
+		Where(sq.Eq{
+			"component_product_id": id,
+		}).
+		RunWith(repo.DB).
+		QueryRow().
+		Scan(&count)
+
+	return count, err
+}
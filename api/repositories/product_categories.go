@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"api/domain"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func insertProductCategories(
+	tx *sql.Tx,
+	id domain.ProductId,
+	categoryIDs []domain.CategoryId,
+) error {
+
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	categoryInsert := sq.Insert("product_category").
+		Columns("product_id", "category_id")
+
+	for _, categoryID := range categoryIDs {
+		categoryInsert = categoryInsert.Values(id, categoryID)
+	}
+
+	_, err := categoryInsert.RunWith(tx).Exec()
+
+	return err
+}
+
+func (repo ProductRepositoryImpl) getProductCategories(
+	predicate interface{},
+) (map[domain.ProductId][]domain.CategoryId, error) {
+
+	rows, err := sq.Select("product_id", "category_id").
+		From("product_category").
+		Where(predicate).
+		OrderBy("product_id", "category_id").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	categories := map[domain.ProductId][]domain.CategoryId{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		var categoryID domain.CategoryId
+
+		err := rows.Scan(&productID, &categoryID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		categories[productID] = append(categories[productID], categoryID)
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"api/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func getProductChannels(
+	runner sq.BaseRunner,
+	predicate interface{},
+) (map[domain.ProductId][]domain.ProductChannel, error) {
+
+	rows, err := sq.Select("product_id", "channel", "published", "title", "price").
+		From("product_channel").
+		Where(predicate).
+		OrderBy("product_id", "channel").
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	channels := map[domain.ProductId][]domain.ProductChannel{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		channel := domain.ProductChannel{}
+
+		err := rows.Scan(
+			&productID,
+			&channel.Channel,
+			&channel.Published,
+			&channel.Title,
+			&channel.Price,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		channels[productID] = append(channels[productID], channel)
+	}
+
+	return channels, nil
+}
+
+func (repo ProductRepositoryImpl) GetChannels(
+	id domain.ProductId,
+) ([]domain.ProductChannel, error) {
+
+	channels, err := getProductChannels(repo.DB, sq.Eq{
+		"product_id": id,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if channels[id] == nil {
+		return []domain.ProductChannel{}, nil
+	}
+
+	return channels[id], nil
+}
+
+// SetChannels replaces all of the channels of the product
+func (repo ProductRepositoryImpl) SetChannels(
+	id domain.ProductId,
+	channels []domain.ProductChannel,
+) error {
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Delete("product_channel").
+		Where(sq.Eq{
+			"product_id": id,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(channels) > 0 {
+		channelInsert := sq.Insert("product_channel").
+			Columns("product_id", "channel", "published", "title", "price")
+
+		for _, channel := range channels {
+			channelInsert = channelInsert.Values(
+				id,
+				channel.Channel,
+				channel.Published,
+				channel.Title,
+				channel.Price,
+			)
+		}
+
+		_, err = channelInsert.RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo ProductRepositoryImpl) GetChannelOverrides(
+	channel string,
+	ids []domain.ProductId,
+) (map[domain.ProductId]domain.ProductChannel, error) {
+
+	overrides := map[domain.ProductId]domain.ProductChannel{}
+
+	if len(ids) == 0 {
+		return overrides, nil
+	}
+
+	channels, err := getProductChannels(repo.DB, sq.Eq{
+		"product_id": ids,
+		"channel":    channel,
+		"published":  true,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for productID, productChannels := range channels {
+		overrides[productID] = productChannels[0]
+	}
+
+	return overrides, nil
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"api/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (repo ProductRepositoryImpl) GetRelations(
+	ids []domain.ProductId,
+) (map[domain.ProductId][]domain.ProductRelation, error) {
+
+	relations := map[domain.ProductId][]domain.ProductRelation{}
+
+	if len(ids) == 0 {
+		return relations, nil
+	}
+
+	rows, err := sq.Select("product_id", "relation_type", "related_product_id", "sort_order").
+		From("product_relation").
+		Where(sq.Eq{
+			"product_id": ids,
+		}).
+		OrderBy("product_id", "relation_type", "sort_order", "related_product_id").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID domain.ProductId
+		relation := domain.ProductRelation{}
+
+		err := rows.Scan(&productID, &relation.Type, &relation.ProductID, &relation.SortOrder)
+
+		if err != nil {
+			return nil, err
+		}
+
+		relations[productID] = append(relations[productID], relation)
+	}
+
+	return relations, nil
+}
+
+// SetRelations replaces all of the relations of the product
+func (repo ProductRepositoryImpl) SetRelations(
+	id domain.ProductId,
+	relations []domain.ProductRelation,
+) error {
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.Delete("product_relation").
+		Where(sq.Eq{
+			"product_id": id,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(relations) > 0 {
+		relationInsert := sq.Insert("product_relation").
+			Columns("product_id", "relation_type", "related_product_id", "sort_order")
+
+		for _, relation := range relations {
+			relationInsert = relationInsert.Values(
+				id,
+				relation.Type,
+				relation.ProductID,
+				relation.SortOrder,
+			)
+		}
+
+		_, err = relationInsert.RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -4,6 +4,8 @@ import (
 	"api/domain"
 	"api/util"
 	"database/sql"
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -11,8 +13,14 @@ import (
 	sq "github.com/Masterminds/squirrel"
 )
 
+/*
+Every query is scoped to the tenant in the metadata. Tables
+that hang off a product by its id don't need a tenant of
+their own since the product was already found within it.
+*/
 type ProductRepositoryImpl struct {
-	DB *sql.DB
+	DB       *sql.DB
+	Metadata util.Metadata
 }
 
 func fieldsToMap(fields []string) map[string]struct{} {
@@ -116,10 +124,13 @@ func rowToProduct(
 	toScan = addToScan(toScan, fieldMap, "productId", &product.ProductID)
 	toScan = addToScan(toScan, fieldMap, "title", &product.Title)
 	toScan = addToScan(toScan, fieldMap, "sku", &product.Sku)
+	toScan = addToScan(toScan, fieldMap, "status", &product.Status)
 	toScan = addToScan(toScan, fieldMap, "description", &product.Description)
 	toScan = addToScan(toScan, fieldMap, "price", &product.Price)
 	toScan = addToScan(toScan, fieldMap, "created", &created)
 	toScan = addToScan(toScan, fieldMap, "lastUpdated", &lastUpdated)
+	toScan = addToScan(toScan, fieldMap, "parentId", &product.ParentID)
+	toScan = addToScan(toScan, fieldMap, "brandId", &product.BrandID)
 
 	err := rows.Scan(toScan...)
 
@@ -173,11 +184,92 @@ func getWhereIn(
 	return whereBuilder.String()
 }
 
+/*
+Both the product listing and the facet counts need to
+narrow down the products in the exact same way so the
+filter is applied in one place. Every query passed in
+here needs to be joined with product_barcode.
+*/
+func applyProductFilter(
+	query sq.SelectBuilder,
+	tenant string,
+	filter domain.ProductFilter,
+) sq.SelectBuilder {
+
+	query = query.Where(sq.Eq{
+		"product.tenant": tenant,
+	})
+
+	if filter.Sku != "" {
+		query = query.Where(sq.Eq{
+			"product.sku": filter.Sku,
+		})
+	}
+
+	if filter.Barcode != "" {
+		query = query.Where(sq.Eq{
+			"product_barcode.barcode": filter.Barcode,
+		})
+	}
+
+	if filter.ParentID != nil {
+		query = query.Where(sq.Eq{
+			"product.parent_id": *filter.ParentID,
+		})
+	}
+
+	if filter.BrandID != nil {
+		query = query.Where(sq.Eq{
+			"product.brand_id": *filter.BrandID,
+		})
+	}
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where(sq.Eq{
+			"product.status": filter.Statuses,
+		})
+	}
+
+	if filter.Channel != "" {
+		channelQuery, args, _ := sq.Select("product_id").
+			From("product_channel").
+			Where(sq.Eq{
+				"channel":   filter.Channel,
+				"published": true,
+			}).
+			ToSql()
+
+		query = query.Where("product.product_id IN("+channelQuery+")", args...)
+	}
+
+	if filter.ProductIDs != nil {
+		query = query.Where(sq.Eq{
+			"product.product_id": filter.ProductIDs,
+		})
+	}
+
+	/*
+		An empty list still filters, it just means that there
+		are no categories that any product could be in.
+	*/
+	if filter.CategoryIDs != nil {
+		categoryQuery, args, _ := sq.Select("product_id").
+			From("product_category").
+			Where(sq.Eq{
+				"category_id": filter.CategoryIDs,
+			}).
+			ToSql()
+
+		query = query.Where("product.product_id IN("+categoryQuery+")", args...)
+	}
+
+	return query
+}
+
 func (repo ProductRepositoryImpl) GetProducts(
 	start uint64,
 	num uint64,
-	sku string,
-	barcode string,
+	filter domain.ProductFilter,
 	fields []string,
 ) ([]domain.Product, uint32, error) {
 
@@ -192,41 +284,37 @@ func (repo ProductRepositoryImpl) GetProducts(
 	toSelect = addToSelect(toSelect, fieldMap, "productId", "product.product_id")
 	toSelect = addToSelect(toSelect, fieldMap, "title", "product.title")
 	toSelect = addToSelect(toSelect, fieldMap, "sku", "product.sku")
+	toSelect = addToSelect(toSelect, fieldMap, "status", "product.status")
 	toSelect = addToSelect(toSelect, fieldMap, "description", "product.description")
 	toSelect = addToSelect(toSelect, fieldMap, "price", "product.price")
 	toSelect = addToSelect(toSelect, fieldMap, "created", "product.created")
 	toSelect = addToSelect(toSelect, fieldMap, "lastUpdated", "product.last_updated")
+	toSelect = addToSelect(toSelect, fieldMap, "parentId", "product.parent_id")
+	toSelect = addToSelect(toSelect, fieldMap, "brandId", "product.brand_id")
 
+	/*
+		The barcodes are only joined in to filter on them. A
+		product has one row per barcode so we group by product,
+		otherwise the limit would count barcodes and a page
+		could come back with fewer products than asked for.
+	*/
 	query := sq.Select(toSelect...).
 		LeftJoin("product_barcode USING (product_id)").
 		From("product").
+		GroupBy("product.product_id").
 		Limit(num).
 		Offset(start)
 
-	if sku != "" {
-		predicate := sq.Eq{
-			"product.sku": sku,
-		}
-
-		query = query.Where(predicate)
-		/*
-			Usually the count is used for pagination in Tables
-			that show the data. Therefore we need to filter the
-			count based on our current filter. It would be strange
-			if our table said for example 0/1000 but you could only
-			get one record to display with your current filter.
-		*/
-		countQuery = countQuery.Where(predicate)
-	}
-
-	if barcode != "" {
-		predicate := sq.Eq{
-			"product_barcode.barcode": barcode,
-		}
+	query = applyProductFilter(query, repo.Metadata.Tenant, filter)
 
-		query = query.Where(predicate)
-		countQuery = countQuery.Where(predicate)
-	}
+	/*
+		Usually the count is used for pagination in Tables
+		that show the data. Therefore we need to filter the
+		count based on our current filter. It would be strange
+		if our table said for example 0/1000 but you could only
+		get one record to display with your current filter.
+	*/
+	countQuery = applyProductFilter(countQuery, repo.Metadata.Tenant, filter)
 
 	rows, err := query.RunWith(repo.DB).Query()
 
@@ -334,6 +422,92 @@ func (repo ProductRepositoryImpl) GetProducts(
 				productsMap[productID] = product
 			}
 		}
+
+		_, hasTranslationsField := fieldMap["translations"]
+
+		if hasTranslationsField || len(fieldMap) == 0 {
+			translations, err := repo.getTranslations(inBuilder.String())
+
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for productID, productTranslations := range translations {
+				product := productsMap[productID]
+				product.Translations = productTranslations
+
+				productsMap[productID] = product
+			}
+		}
+
+		_, hasVariantAxesField := fieldMap["variantAxes"]
+
+		if hasVariantAxesField || len(fieldMap) == 0 {
+			variantAxes, err := repo.getVariantAxes(inBuilder.String())
+
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for productID, axes := range variantAxes {
+				product := productsMap[productID]
+				product.VariantAxes = axes
+
+				productsMap[productID] = product
+			}
+		}
+
+		_, hasCategoryIDsField := fieldMap["categoryIds"]
+
+		if hasCategoryIDsField || len(fieldMap) == 0 {
+			categories, err := repo.getProductCategories(inBuilder.String())
+
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for productID, categoryIDs := range categories {
+				product := productsMap[productID]
+				product.CategoryIDs = categoryIDs
+
+				productsMap[productID] = product
+			}
+		}
+
+		_, hasComponentsField := fieldMap["components"]
+
+		if hasComponentsField || len(fieldMap) == 0 {
+			components, err := repo.getBundleComponents(inBuilder.String())
+
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for productID, bundleComponents := range components {
+				product := productsMap[productID]
+				product.Components = bundleComponents
+
+				productsMap[productID] = product
+			}
+		}
+
+		// Stock is left out unless it is asked for
+		_, hasStockField := fieldMap["stock"]
+
+		if hasStockField {
+			stock, err := getStockLevels(repo.DB, repo.Metadata.Tenant, inBuilder.String())
+
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for productID, levels := range stock {
+				product := productsMap[productID]
+				product.Stock = levels
+
+				productsMap[productID] = product
+			}
+		}
 	}
 
 	countQueryString, args, err := countQuery.ToSql()
@@ -357,6 +531,121 @@ func (repo ProductRepositoryImpl) GetProducts(
 	return products, count, nil
 }
 
+/*
+The facets are counted over every product matching the
+filter and not just the current page, the same way that
+the total count is.
+*/
+func (repo ProductRepositoryImpl) GetProductFacets(
+	filter domain.ProductFilter,
+	priceBuckets []string,
+) (*domain.ProductFacets, error) {
+
+	matching := sq.Select("DISTINCT product.product_id").
+		From("product").
+		LeftJoin("product_barcode USING (product_id)")
+
+	matchingQuery, matchingArgs, err := applyProductFilter(matching, repo.Metadata.Tenant, filter).ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	inMatching := "product_id IN(" + matchingQuery + ")"
+
+	attributeRows, err := sq.Select("name", "value", "count(*)").
+		From("product_attribute").
+		Where(inMatching, matchingArgs...).
+		GroupBy("name", "value").
+		OrderBy("name", "value").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer attributeRows.Close()
+
+	facets := domain.ProductFacets{
+		Attributes: []domain.AttributeFacet{},
+		Prices:     []domain.PriceBucketFacet{},
+	}
+
+	for attributeRows.Next() {
+		var name string
+		valueCount := domain.AttributeValueCount{}
+
+		err := attributeRows.Scan(&name, &valueCount.Value, &valueCount.Count)
+
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(facets.Attributes) - 1
+
+		if last < 0 || facets.Attributes[last].Name != name {
+			facets.Attributes = append(facets.Attributes, domain.AttributeFacet{
+				Name: name,
+			})
+			last++
+		}
+
+		facets.Attributes[last].Values = append(facets.Attributes[last].Values, valueCount)
+	}
+
+	if len(priceBuckets) == 0 {
+		return &facets, nil
+	}
+
+	/*
+		Every bucket becomes its own column so that all of
+		them can be counted in a single pass over the products.
+		The last bucket has no upper bound.
+	*/
+	priceQuery := sq.Select().
+		From("product").
+		Where(inMatching, matchingArgs...)
+
+	for i, from := range priceBuckets {
+		bucket := domain.PriceBucketFacet{
+			From: from,
+		}
+
+		if i+1 < len(priceBuckets) {
+			to := priceBuckets[i+1]
+			bucket.To = &to
+
+			priceQuery = priceQuery.Column(
+				"COALESCE(SUM(CASE WHEN price >= ? AND price < ? THEN 1 ELSE 0 END), 0)",
+				from,
+				to,
+			)
+		} else {
+			priceQuery = priceQuery.Column(
+				"COALESCE(SUM(CASE WHEN price >= ? THEN 1 ELSE 0 END), 0)",
+				from,
+			)
+		}
+
+		facets.Prices = append(facets.Prices, bucket)
+	}
+
+	toScan := make([]interface{}, len(facets.Prices))
+
+	for i := range facets.Prices {
+		toScan[i] = &facets.Prices[i].Count
+	}
+
+	err = priceQuery.RunWith(repo.DB).QueryRow().Scan(toScan...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
 func (repo ProductRepositoryImpl) GetProduct(
 	id domain.ProductId,
 	fields []string,
@@ -368,19 +657,27 @@ func (repo ProductRepositoryImpl) GetProduct(
 		"product_id": id,
 	}
 
+	tenantPredicate := sq.Eq{
+		"product_id": id,
+		"tenant":     repo.Metadata.Tenant,
+	}
+
 	toSelect := []string{}
 
 	toSelect = addToSelect(toSelect, fieldMap, "productId", "product_id")
 	toSelect = addToSelect(toSelect, fieldMap, "title", "title")
 	toSelect = addToSelect(toSelect, fieldMap, "sku", "sku")
+	toSelect = addToSelect(toSelect, fieldMap, "status", "status")
 	toSelect = addToSelect(toSelect, fieldMap, "description", "description")
 	toSelect = addToSelect(toSelect, fieldMap, "price", "price")
 	toSelect = addToSelect(toSelect, fieldMap, "created", "created")
 	toSelect = addToSelect(toSelect, fieldMap, "lastUpdated", "last_updated")
+	toSelect = addToSelect(toSelect, fieldMap, "parentId", "parent_id")
+	toSelect = addToSelect(toSelect, fieldMap, "brandId", "brand_id")
 
 	rows, err := sq.Select(toSelect...).
 		From("product").
-		Where(predicate).
+		Where(tenantPredicate).
 		RunWith(repo.DB).
 		Query()
 
@@ -462,11 +759,168 @@ func (repo ProductRepositoryImpl) GetProduct(
 		}
 	}
 
+	_, hasTranslationsField := fieldMap["translations"]
+
+	if hasTranslationsField || len(fields) == 0 {
+		translations, err := repo.getTranslations(predicate)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		product.Translations = translations[id]
+	}
+
+	_, hasVariantAxesField := fieldMap["variantAxes"]
+
+	if hasVariantAxesField || len(fields) == 0 {
+		variantAxes, err := repo.getVariantAxes(predicate)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		product.VariantAxes = variantAxes[id]
+	}
+
+	_, hasCategoryIDsField := fieldMap["categoryIds"]
+
+	if hasCategoryIDsField || len(fields) == 0 {
+		categories, err := repo.getProductCategories(predicate)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		product.CategoryIDs = categories[id]
+	}
+
+	_, hasComponentsField := fieldMap["components"]
+
+	if hasComponentsField || len(fields) == 0 {
+		components, err := repo.getBundleComponents(predicate)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		product.Components = components[id]
+	}
+
+	_, hasStockField := fieldMap["stock"]
+
+	if hasStockField {
+		stock, err := getStockLevels(repo.DB, repo.Metadata.Tenant, predicate)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		product.Stock = stock[id]
+	}
+
 	return product, true, nil
 }
 
+/*
+Generated barcodes are EAN-13 barcodes made up of the prefix,
+an item reference and a check digit. The next item reference
+for every prefix is kept in barcode_sequence which is locked
+for the rest of the transaction so that two requests can not
+hand out the same barcode.
+
+A reference that is already taken by a manually added barcode
+is skipped, no matter which tenant it belongs to.
+*/
+func generateBarcode(
+	tx *sql.Tx,
+	tenant string,
+	id domain.ProductId,
+	barcodePrefix string,
+) (string, error) {
+
+	_, err := sq.Insert("barcode_sequence").
+		Columns("prefix", "next_reference").
+		Values(barcodePrefix, 1).
+		Suffix("ON DUPLICATE KEY UPDATE prefix = prefix").
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		return "", err
+	}
+
+	predicate := sq.Eq{
+		"prefix": barcodePrefix,
+	}
+
+	var reference uint64
+
+	err = sq.Select("next_reference").
+		From("barcode_sequence").
+		Where(predicate).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRow().
+		Scan(&reference)
+
+	if err != nil {
+		return "", err
+	}
+
+	referenceDigits := 12 - len(barcodePrefix)
+	referenceLimit := uint64(math.Pow10(referenceDigits))
+
+	for ; reference < referenceLimit; reference++ {
+		data := barcodePrefix + fmt.Sprintf("%0*d", referenceDigits, reference)
+		barcode := data + string(util.GetGS1CheckDigit(data))
+
+		var taken uint32
+
+		err := sq.Select("count(*)").
+			From("product_barcode").
+			Where(sq.Eq{"barcode": barcode}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&taken)
+
+		if err != nil {
+			return "", err
+		}
+
+		if taken > 0 {
+			continue
+		}
+
+		_, err = sq.Insert("product_barcode").
+			Columns("tenant", "product_id", "barcode").
+			Values(tenant, id, barcode).
+			RunWith(tx).
+			Exec()
+
+		if err != nil {
+			return "", err
+		}
+
+		_, err = sq.Update("barcode_sequence").
+			Set("next_reference", reference+1).
+			Where(predicate).
+			RunWith(tx).
+			Exec()
+
+		if err != nil {
+			return "", err
+		}
+
+		return barcode, nil
+	}
+
+	return "", domain.ErrBarcodeRangeExhausted
+}
+
 func (repo ProductRepositoryImpl) AddProduct(
 	product domain.ProductAddInput,
+	barcodePrefix string,
 ) (domain.ProductId, error) {
 
 	price, err := strconv.ParseFloat(*product.Price, 32)
@@ -484,6 +938,12 @@ func (repo ProductRepositoryImpl) AddProduct(
 		}
 	}
 
+	status := domain.ProductStatusActive
+
+	if product.Status != nil {
+		status = *product.Status
+	}
+
 	tx, err := repo.DB.Begin()
 
 	if err != nil {
@@ -492,13 +952,27 @@ func (repo ProductRepositoryImpl) AddProduct(
 
 	query, args, err := sq.Insert("product").
 		Columns(
+			"tenant",
 			"title",
 			"sku",
 			"description",
 			"price",
 			"created",
+			"parent_id",
+			"brand_id",
+			"status",
+		).
+		Values(
+			repo.Metadata.Tenant,
+			product.Title,
+			product.Sku,
+			description,
+			price,
+			time.Now(),
+			product.ParentID,
+			product.BrandID,
+			status,
 		).
-		Values(product.Title, product.Sku, description, price, time.Now()).
 		ToSql()
 
 	if err != nil {
@@ -524,10 +998,10 @@ func (repo ProductRepositoryImpl) AddProduct(
 	var productID = domain.ProductId(id)
 
 	if len(product.Barcodes) > 0 {
-		barcodeInsert := sq.Insert("product_barcode").Columns("product_id", "barcode")
+		barcodeInsert := sq.Insert("product_barcode").Columns("tenant", "product_id", "barcode")
 
 		for _, barcode := range product.Barcodes {
-			barcodeInsert = barcodeInsert.Values(productID, barcode)
+			barcodeInsert = barcodeInsert.Values(repo.Metadata.Tenant, productID, barcode)
 		}
 
 		_, err := barcodeInsert.RunWith(tx).Query()
@@ -553,6 +1027,43 @@ func (repo ProductRepositoryImpl) AddProduct(
 		}
 	}
 
+	err = insertTranslations(tx, productID, product.Translations)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = insertVariantAxes(tx, productID, product.VariantAxes)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = insertProductCategories(tx, productID, product.CategoryIDs)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = insertBundleComponents(tx, productID, product.Components)
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if barcodePrefix != "" {
+		_, err := generateBarcode(tx, repo.Metadata.Tenant, productID, barcodePrefix)
+
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -571,7 +1082,12 @@ func (repo ProductRepositoryImpl) UpdateProduct(
 		"product_id": id,
 	}
 
-	query := sq.Update("product").Set("last_updated", time.Now()).Where(predicate)
+	query := sq.Update("product").
+		Set("last_updated", time.Now()).
+		Where(predicate).
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		})
 
 	if product.Title != nil {
 		query = query.Set("title", product.Title)
@@ -589,6 +1105,16 @@ func (repo ProductRepositoryImpl) UpdateProduct(
 		query = query.Set("price", product.Price)
 	}
 
+	if product.BrandID != nil && *product.BrandID == 0 {
+		query = query.Set("brand_id", nil)
+	} else if product.BrandID != nil {
+		query = query.Set("brand_id", product.BrandID)
+	}
+
+	if product.Status != nil {
+		query = query.Set("status", product.Status)
+	}
+
 	//Transaction for the same reason as the func above
 	tx, err := repo.DB.Begin()
 
@@ -612,10 +1138,10 @@ func (repo ProductRepositoryImpl) UpdateProduct(
 		}
 
 		if len(product.Barcodes) > 0 {
-			barcodeInsert := sq.Insert("product_barcode").Columns("product_id", "barcode")
+			barcodeInsert := sq.Insert("product_barcode").Columns("tenant", "product_id", "barcode")
 
 			for _, barcode := range product.Barcodes {
-				barcodeInsert = barcodeInsert.Values(id, barcode)
+				barcodeInsert = barcodeInsert.Values(repo.Metadata.Tenant, id, barcode)
 			}
 
 			_, err = barcodeInsert.RunWith(tx).Query()
@@ -651,6 +1177,70 @@ func (repo ProductRepositoryImpl) UpdateProduct(
 		}
 	}
 
+	if product.Translations != nil {
+		_, err = sq.Delete("product_translation").Where(predicate).RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertTranslations(tx, id, product.Translations)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if product.VariantAxes != nil {
+		_, err = sq.Delete("product_variant_axis").Where(predicate).RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertVariantAxes(tx, id, product.VariantAxes)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if product.CategoryIDs != nil {
+		_, err = sq.Delete("product_category").Where(predicate).RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertProductCategories(tx, id, product.CategoryIDs)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if product.Components != nil {
+		_, err = sq.Delete("product_bundle_component").Where(predicate).RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		err = insertBundleComponents(tx, id, product.Components)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
@@ -667,7 +1257,13 @@ func (repo ProductRepositoryImpl) DeleteProduct(
 		return err
 	}
 
-	_, err = sq.Delete("product").Where(predicate).RunWith(tx).Query()
+	_, err = sq.Delete("product").
+		Where(predicate).
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		}).
+		RunWith(tx).
+		Query()
 
 	if err != nil {
 		tx.Rollback()
@@ -688,11 +1284,92 @@ func (repo ProductRepositoryImpl) DeleteProduct(
 		return err
 	}
 
+	_, err = sq.Delete("product_translation").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = sq.Delete("product_variant_axis").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = sq.Delete("product_category").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = sq.Delete("product_bundle_component").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	/*
+		Relations pointing towards the product are removed as
+		well so no other product is left linking to nothing.
+	*/
+	_, err = sq.Delete("product_relation").
+		Where(sq.Or{
+			predicate,
+			sq.Eq{
+				"related_product_id": id,
+			},
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = sq.Delete("product_channel").Where(predicate).RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	tx.Commit()
 
 	return nil
 }
 
+func (repo ProductRepositoryImpl) GenerateBarcode(
+	id domain.ProductId,
+	barcodePrefix string,
+) (string, error) {
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return "", err
+	}
+
+	barcode, err := generateBarcode(tx, repo.Metadata.Tenant, id, barcodePrefix)
+
+	if err != nil {
+		tx.Rollback()
+		return "", err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return "", err
+	}
+
+	return barcode, nil
+}
+
 /*
 The functions below are used for validation
 by the service
@@ -702,7 +1379,11 @@ func (repo ProductRepositoryImpl) ProductExists(
 	id domain.ProductId,
 ) (bool, error) {
 
-	count, err := repo.count("SELECT COUNT(*) as count FROM product WHERE product_id = ?", id)
+	count, err := repo.count(
+		"SELECT COUNT(*) as count FROM product WHERE product_id = ? AND tenant = ?",
+		id,
+		repo.Metadata.Tenant,
+	)
 
 	if err != nil {
 		return false, err
@@ -716,7 +1397,8 @@ func (repo ProductRepositoryImpl) GetSku(
 ) (*domain.ProductSku, error) {
 
 	predicate := sq.Eq{
-		"sku": sku,
+		"sku":    sku,
+		"tenant": repo.Metadata.Tenant,
 	}
 
 	rows, err := sq.Select("product_id", "sku").
@@ -757,7 +1439,10 @@ func (repo ProductRepositoryImpl) GetBarcodes(
 	interfaces := util.StringsToInterfaces(barcodes)
 	whereIn := getWhereIn("barcode", len(barcodes))
 
-	query = query.Where(whereIn, interfaces...)
+	query = query.Where(whereIn, interfaces...).
+		Where(sq.Eq{
+			"tenant": repo.Metadata.Tenant,
+		})
 
 	rows, err := query.RunWith(repo.DB).Query()
 
@@ -779,3 +1464,43 @@ func (repo ProductRepositoryImpl) GetBarcodes(
 
 	return productBarcodes, nil
 }
+
+/*
+Used once on startup to fill the in memory suggestion
+index, after that the service keeps it up to date. The
+suggestions of every tenant are read at once.
+*/
+func (repo ProductRepositoryImpl) GetSuggestions() ([]domain.ProductSuggestion, error) {
+
+	rows, err := sq.Select("tenant", "product_id", "title", "sku").
+		From("product").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	suggestions := []domain.ProductSuggestion{}
+
+	for rows.Next() {
+		suggestion := domain.ProductSuggestion{}
+
+		err := rows.Scan(
+			&suggestion.Tenant,
+			&suggestion.ProductID,
+			&suggestion.Title,
+			&suggestion.Sku,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
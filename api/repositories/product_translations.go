@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"api/domain"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func insertTranslations(
+	tx *sql.Tx,
+	id domain.ProductId,
+	translations map[string]domain.ProductTranslation,
+) error {
+
+	if len(translations) == 0 {
+		return nil
+	}
+
+	translationInsert := sq.Insert("product_translation").
+		Columns("product_id", "locale", "title", "description")
+
+	for locale, translation := range translations {
+		translationInsert = translationInsert.Values(
+			id,
+			locale,
+			translation.Title,
+			translation.Description,
+		)
+	}
+
+	_, err := translationInsert.RunWith(tx).Exec()
+
+	return err
+}
+
+/*
+Reads the translations of every product matching the
+predicate grouped by product and then by locale.
+*/
+func (repo ProductRepositoryImpl) getTranslations(
+	predicate interface{},
+) (map[domain.ProductId]map[string]domain.ProductTranslation, error) {
+
+	rows, err := sq.Select("product_id", "locale", "title", "description").
+		From("product_translation").
+		Where(predicate).
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	translations := map[domain.ProductId]map[string]domain.ProductTranslation{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		var locale string
+		translation := domain.ProductTranslation{}
+
+		err := rows.Scan(&productID, &locale, &translation.Title, &translation.Description)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if translations[productID] == nil {
+			translations[productID] = map[string]domain.ProductTranslation{}
+		}
+
+		translations[productID][locale] = translation
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"api/domain"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func insertVariantAxes(
+	tx *sql.Tx,
+	id domain.ProductId,
+	axes []string,
+) error {
+
+	if len(axes) == 0 {
+		return nil
+	}
+
+	axisInsert := sq.Insert("product_variant_axis").
+		Columns("product_id", "name", "sort_order")
+
+	for i, axis := range axes {
+		axisInsert = axisInsert.Values(id, axis, i)
+	}
+
+	_, err := axisInsert.RunWith(tx).Exec()
+
+	return err
+}
+
+/*
+Reads the variant axes of every product matching the
+predicate in the order they were given in.
+*/
+func (repo ProductRepositoryImpl) getVariantAxes(
+	predicate interface{},
+) (map[domain.ProductId][]string, error) {
+
+	rows, err := sq.Select("product_id", "name").
+		From("product_variant_axis").
+		Where(predicate).
+		OrderBy("product_id", "sort_order").
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	axes := map[domain.ProductId][]string{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		var name string
+
+		err := rows.Scan(&productID, &name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		axes[productID] = append(axes[productID], name)
+	}
+
+	return axes, nil
+}
+
+func (repo ProductRepositoryImpl) GetVariantAttributes(
+	parentID domain.ProductId,
+) (map[domain.ProductId][]domain.ProductAttribute, error) {
+
+	rows, err := sq.Select("product_id", "name", "value").
+		From("product_attribute").
+		Where("product_id IN(SELECT product_id FROM product WHERE parent_id = ?)", parentID).
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	attributes := map[domain.ProductId][]domain.ProductAttribute{}
+
+	for rows.Next() {
+		var productID domain.ProductId
+		attribute := domain.ProductAttribute{}
+
+		err := rows.Scan(&productID, &attribute.Name, &attribute.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attributes[productID] = append(attributes[productID], attribute)
+	}
+
+	return attributes, nil
+}
+
+func (repo ProductRepositoryImpl) CountVariants(
+	parentID domain.ProductId,
+) (uint32, error) {
+
+	query, args, err := sq.Select("count(*)").
+		From("product").
+		Where(sq.Eq{
+			"parent_id": parentID,
+		}).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return repo.count(query, args...)
+}
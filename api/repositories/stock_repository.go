@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+/*
+Movements and reservations are scoped to the tenant in the
+metadata. The reservation sweeper runs without one and expires
+reservations of every tenant.
+*/
+type StockRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+/*
+The stock levels are derived from the movements and the
+active reservations every time they are read. Shared between
+the stock repository and the product repository, which reads
+the stock when it is asked for as one of the product fields.
+*/
+func getStockLevels(
+	runner sq.BaseRunner,
+	tenant string,
+	predicate interface{},
+) (map[domain.ProductId][]domain.StockLevel, error) {
+
+	tenantPredicate := sq.Eq{
+		"tenant": tenant,
+	}
+
+	rows, err := sq.Select("product_id", "location", "SUM(delta)", "MAX(created)").
+		From("stock_movement").
+		Where(tenantPredicate).
+		Where(predicate).
+		GroupBy("product_id", "location").
+		OrderBy("product_id", "location").
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	levels := map[domain.ProductId][]domain.StockLevel{}
+	levelIndexes := map[domain.ProductId]map[string]int{}
+
+	for rows.Next() {
+		level := domain.StockLevel{}
+		var lastUpdated string
+
+		err := rows.Scan(&level.ProductID, &level.Location, &level.OnHand, &lastUpdated)
+
+		if err != nil {
+			return nil, err
+		}
+
+		level.LastUpdated, err = convertSQLDateToTimestamp(lastUpdated)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if levelIndexes[level.ProductID] == nil {
+			levelIndexes[level.ProductID] = map[string]int{}
+		}
+
+		levelIndexes[level.ProductID][level.Location] = len(levels[level.ProductID])
+		levels[level.ProductID] = append(levels[level.ProductID], level)
+	}
+
+	reservedRows, err := sq.Select("product_id", "location", "SUM(quantity)").
+		From("stock_reservation").
+		Where(tenantPredicate).
+		Where(predicate).
+		Where(sq.Eq{
+			"status": domain.ReservationStatusActive,
+		}).
+		Where("expires > ?", time.Now()).
+		GroupBy("product_id", "location").
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reservedRows.Close()
+
+	for reservedRows.Next() {
+		var productID domain.ProductId
+		var location string
+		var reserved int64
+
+		err := reservedRows.Scan(&productID, &location, &reserved)
+
+		if err != nil {
+			return nil, err
+		}
+
+		index, exists := levelIndexes[productID][location]
+
+		if !exists {
+			index = len(levels[productID])
+			levels[productID] = append(levels[productID], domain.StockLevel{
+				ProductID: productID,
+				Location:  location,
+			})
+		}
+
+		levels[productID][index].Reserved = reserved
+	}
+
+	for _, productLevels := range levels {
+		for i := range productLevels {
+			productLevels[i].Available = productLevels[i].OnHand - productLevels[i].Reserved
+		}
+	}
+
+	return levels, nil
+}
+
+/*
+Locks the product rows so anything that writes movements for
+the same products happens one at a time, the levels read after
+the lock stay valid until the transaction ends. The rows are
+locked in id order so two overlapping locks can't deadlock.
+Returns the ids that were found.
+*/
+func lockProducts(
+	tx sq.BaseRunner,
+	tenant string,
+	ids []domain.ProductId,
+) ([]domain.ProductId, error) {
+
+	locked := []domain.ProductId{}
+
+	if len(ids) == 0 {
+		return locked, nil
+	}
+
+	rows, err := sq.Select("product_id").
+		From("product").
+		Where(sq.Eq{
+			"tenant":     tenant,
+			"product_id": ids,
+		}).
+		OrderBy("product_id").
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id domain.ProductId
+
+		err := rows.Scan(&id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		locked = append(locked, id)
+	}
+
+	return locked, rows.Err()
+}
+
+func (repo StockRepositoryImpl) GetStockLevels(
+	id domain.ProductId,
+) ([]domain.StockLevel, error) {
+
+	levels, err := getStockLevels(repo.DB, repo.Metadata.Tenant, sq.Eq{
+		"product_id": id,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if levels[id] == nil {
+		return []domain.StockLevel{}, nil
+	}
+
+	return levels[id], nil
+}
+
+/*
+All of the movements are written in one transaction so a
+transfer never shows up at only one of its locations. The
+product is locked the same way as for a reservation and the
+new levels are read back inside the same transaction.
+*/
+func (repo StockRepositoryImpl) AddMovements(
+	id domain.ProductId,
+	movements []domain.StockMovement,
+) ([]domain.StockLevel, error) {
+
+	now := time.Now()
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = lockProducts(tx, repo.Metadata.Tenant, []domain.ProductId{id})
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	movementInsert := sq.Insert("stock_movement").
+		Columns("tenant", "product_id", "location", "delta", "reason", "reference", "created")
+
+	locations := []string{}
+
+	for _, movement := range movements {
+		movementInsert = movementInsert.Values(
+			repo.Metadata.Tenant,
+			id,
+			movement.Location,
+			movement.Delta,
+			movement.Reason,
+			movement.Reference,
+			now,
+		)
+
+		locations = append(locations, movement.Location)
+	}
+
+	_, err = movementInsert.RunWith(tx).Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	levels, err := getStockLevels(tx, repo.Metadata.Tenant, sq.Eq{
+		"product_id": id,
+		"location":   locations,
+	})
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return levels[id], nil
+}
+
+// The newest movements come first
+func (repo StockRepositoryImpl) GetMovements(
+	id domain.ProductId,
+	location string,
+	start uint64,
+	num uint64,
+) ([]domain.StockMovement, uint32, error) {
+
+	predicate := sq.Eq{
+		"tenant":     repo.Metadata.Tenant,
+		"product_id": id,
+	}
+
+	if location != "" {
+		predicate["location"] = location
+	}
+
+	rows, err := sq.Select(
+		"movement_id",
+		"product_id",
+		"location",
+		"delta",
+		"reason",
+		"reference",
+		"created",
+	).
+		From("stock_movement").
+		Where(predicate).
+		OrderBy("movement_id DESC").
+		Limit(num).
+		Offset(start).
+		RunWith(repo.DB).
+		Query()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer rows.Close()
+
+	movements := []domain.StockMovement{}
+
+	for rows.Next() {
+		movement := domain.StockMovement{}
+		var created string
+
+		err := rows.Scan(
+			&movement.MovementID,
+			&movement.ProductID,
+			&movement.Location,
+			&movement.Delta,
+			&movement.Reason,
+			&movement.Reference,
+			&created,
+		)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		movement.Created, err = convertSQLDateToTimestamp(created)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	var count uint32
+
+	err = sq.Select("count(*)").
+		From("stock_movement").
+		Where(predicate).
+		RunWith(repo.DB).
+		QueryRow().
+		Scan(&count)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movements, count, nil
+}
@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"api/domain"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func getReservation(
+	runner sq.BaseRunner,
+	tenant string,
+	id uint64,
+	forUpdate bool,
+) (*domain.StockReservation, bool, error) {
+
+	query := sq.Select(
+		"reservation_id",
+		"product_id",
+		"location",
+		"quantity",
+		"status",
+		"reference",
+		"created",
+		"expires",
+	).
+		From("stock_reservation").
+		Where(sq.Eq{
+			"tenant":         tenant,
+			"reservation_id": id,
+		})
+
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE")
+	}
+
+	rows, err := query.RunWith(runner).Query()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, nil
+	}
+
+	reservation := domain.StockReservation{}
+	var created string
+	var expires string
+
+	err = rows.Scan(
+		&reservation.ReservationID,
+		&reservation.ProductID,
+		&reservation.Location,
+		&reservation.Quantity,
+		&reservation.Status,
+		&reservation.Reference,
+		&created,
+		&expires,
+	)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	reservation.Created, err = convertSQLDateToTimestamp(created)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	reservation.Expires, err = convertSQLDateToTimestamp(expires)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &reservation, true, nil
+}
+
+/*
+Reservations for the same product are made one at a time by
+locking the product row first. Without the lock two checkouts
+could both see the last item as available and both reserve it.
+*/
+func (repo StockRepositoryImpl) ReserveStock(
+	id domain.ProductId,
+	reservation domain.StockReservationInput,
+	expires time.Time,
+) (*domain.StockReservation, error) {
+
+	now := time.Now()
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := lockProducts(tx, repo.Metadata.Tenant, []domain.ProductId{id})
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if len(locked) == 0 {
+		tx.Rollback()
+		return nil, sql.ErrNoRows
+	}
+
+	levels, err := getStockLevels(tx, repo.Metadata.Tenant, sq.Eq{
+		"product_id": id,
+		"location":   reservation.Location,
+	})
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var available int64
+
+	if len(levels[id]) > 0 {
+		available = levels[id][0].Available
+	}
+
+	if available < reservation.Quantity {
+		tx.Rollback()
+		return nil, domain.ErrInsufficientStock
+	}
+
+	res, err := sq.Insert("stock_reservation").
+		Columns("tenant", "product_id", "location", "quantity", "status", "reference", "created", "expires").
+		Values(
+			repo.Metadata.Tenant,
+			id,
+			reservation.Location,
+			reservation.Quantity,
+			domain.ReservationStatusActive,
+			reservation.Reference,
+			now,
+			expires,
+		).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	reservationID, err := res.LastInsertId()
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.StockReservation{
+		ReservationID: uint64(reservationID),
+		ProductID:     id,
+		Location:      reservation.Location,
+		Quantity:      reservation.Quantity,
+		Status:        domain.ReservationStatusActive,
+		Reference:     reservation.Reference,
+		Created:       now.Unix(),
+		Expires:       expires.Unix(),
+	}, nil
+}
+
+func (repo StockRepositoryImpl) GetReservation(
+	id uint64,
+) (*domain.StockReservation, bool, error) {
+
+	return getReservation(repo.DB, repo.Metadata.Tenant, id, false)
+}
+
+/*
+Moves an active reservation to its final status. The row is
+locked so a confirm and a release racing each other can't
+both succeed. When onActive is given it runs in the same
+transaction before the status is changed.
+*/
+func (repo StockRepositoryImpl) finishReservation(
+	id uint64,
+	status string,
+	onActive func(tx sq.BaseRunner, reservation domain.StockReservation) error,
+) (*domain.StockReservation, error) {
+
+	tx, err := repo.DB.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, exists, err := getReservation(tx, repo.Metadata.Tenant, id, true)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	isActive := exists &&
+		reservation.Status == domain.ReservationStatusActive &&
+		reservation.Expires > time.Now().Unix()
+
+	if !isActive {
+		tx.Rollback()
+		return nil, domain.ErrReservationNotActive
+	}
+
+	if onActive != nil {
+		err = onActive(tx, *reservation)
+
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	_, err = sq.Update("stock_reservation").
+		Set("status", status).
+		Where(sq.Eq{
+			"reservation_id": id,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.Status = status
+
+	return reservation, nil
+}
+
+func (repo StockRepositoryImpl) ConfirmReservation(
+	id uint64,
+) (*domain.StockReservation, error) {
+
+	return repo.finishReservation(
+		id,
+		domain.ReservationStatusConfirmed,
+		func(tx sq.BaseRunner, reservation domain.StockReservation) error {
+			reference := fmt.Sprintf("reservation-%v", reservation.ReservationID)
+
+			if reservation.Reference != nil {
+				reference = *reservation.Reference
+			}
+
+			_, err := sq.Insert("stock_movement").
+				Columns("tenant", "product_id", "location", "delta", "reason", "reference", "created").
+				Values(
+					repo.Metadata.Tenant,
+					reservation.ProductID,
+					reservation.Location,
+					-reservation.Quantity,
+					domain.StockReasonSale,
+					reference,
+					time.Now(),
+				).
+				RunWith(tx).
+				Exec()
+
+			return err
+		},
+	)
+}
+
+func (repo StockRepositoryImpl) ReleaseReservation(
+	id uint64,
+) (*domain.StockReservation, error) {
+
+	return repo.finishReservation(id, domain.ReservationStatusReleased, nil)
+}
+
+func (repo StockRepositoryImpl) ExpireReservations(
+	now time.Time,
+) (int64, error) {
+
+	res, err := sq.Update("stock_reservation").
+		Set("status", domain.ReservationStatusExpired).
+		Where(sq.Eq{
+			"status": domain.ReservationStatusActive,
+		}).
+		Where("expires <= ?", now).
+		RunWith(repo.DB).
+		Exec()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,417 @@
+package repositories
+
+import (
+	"api/domain"
+	"api/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Stocktakes are scoped to the tenant in the metadata
+type StocktakeRepositoryImpl struct {
+	DB       *sql.DB
+	Metadata util.Metadata
+}
+
+func (repo StocktakeRepositoryImpl) AddStocktake(
+	location string,
+) (uint64, error) {
+
+	res, err := sq.Insert("stocktake").
+		Columns("tenant", "location", "status", "created").
+		Values(repo.Metadata.Tenant, location, domain.StocktakeStatusOpen, time.Now()).
+		RunWith(repo.DB).
+		Exec()
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+
+	return uint64(id), err
+}
+
+func getStocktake(
+	runner sq.BaseRunner,
+	tenant string,
+	id uint64,
+	forUpdate bool,
+) (*domain.Stocktake, bool, error) {
+
+	query := sq.Select("stocktake_id", "location", "status", "created", "committed").
+		From("stocktake").
+		Where(sq.Eq{
+			"tenant":       tenant,
+			"stocktake_id": id,
+		})
+
+	if forUpdate {
+		query = query.Suffix("FOR UPDATE")
+	}
+
+	rows, err := query.RunWith(runner).Query()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !rows.Next() {
+		rows.Close()
+		return nil, false, rows.Err()
+	}
+
+	stocktake := domain.Stocktake{}
+	var created string
+	var committed *string
+
+	err = rows.Scan(
+		&stocktake.StocktakeID,
+		&stocktake.Location,
+		&stocktake.Status,
+		&created,
+		&committed,
+	)
+
+	/*
+		Inside a transaction the counts are read on the same
+		connection, which can't start a query while this one
+		still has rows to hand out.
+	*/
+	rows.Close()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	stocktake.Created, err = convertSQLDateToTimestamp(created)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if committed != nil {
+		committedTimestamp, err := convertSQLDateToTimestamp(*committed)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		stocktake.Committed = &committedTimestamp
+	}
+
+	countRows, err := sq.Select("product_id", "counted").
+		From("stocktake_count").
+		Where(sq.Eq{
+			"stocktake_id": id,
+		}).
+		OrderBy("product_id").
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer countRows.Close()
+
+	stocktake.Counts = []domain.StocktakeCount{}
+
+	for countRows.Next() {
+		count := domain.StocktakeCount{}
+
+		err := countRows.Scan(&count.ProductID, &count.Counted)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		stocktake.Counts = append(stocktake.Counts, count)
+	}
+
+	return &stocktake, true, nil
+}
+
+func (repo StocktakeRepositoryImpl) GetStocktake(
+	id uint64,
+) (*domain.Stocktake, bool, error) {
+
+	return getStocktake(repo.DB, repo.Metadata.Tenant, id, false)
+}
+
+// Scanning the same product again adds to what was counted
+func (repo StocktakeRepositoryImpl) AddCounts(
+	id uint64,
+	counts []domain.StocktakeCount,
+) error {
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	countInsert := sq.Insert("stocktake_count").
+		Columns("stocktake_id", "product_id", "counted")
+
+	for _, count := range counts {
+		countInsert = countInsert.Values(id, count.ProductID, count.Counted)
+	}
+
+	_, err := countInsert.
+		Suffix("ON DUPLICATE KEY UPDATE counted = counted + VALUES(counted)").
+		RunWith(repo.DB).
+		Exec()
+
+	return err
+}
+
+/*
+Compares what is on hand at the location of the stocktake
+right now with what was counted. Products that weren't counted
+at all count as zero, so stock that wasn't found anywhere is
+written off on commit.
+*/
+func getDiscrepancies(
+	runner sq.BaseRunner,
+	tenant string,
+	stocktake domain.Stocktake,
+) ([]domain.StocktakeDiscrepancy, error) {
+
+	levels, err := getStockLevels(runner, tenant, sq.Eq{
+		"location": stocktake.Location,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	counted := map[domain.ProductId]int64{}
+	productIDs := []domain.ProductId{}
+
+	for _, count := range stocktake.Counts {
+		counted[count.ProductID] = count.Counted
+		productIDs = append(productIDs, count.ProductID)
+	}
+
+	for productID, productLevels := range levels {
+		_, isCounted := counted[productID]
+
+		if !isCounted && productLevels[0].OnHand != 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	sort.Slice(productIDs, func(i, j int) bool {
+		return productIDs[i] < productIDs[j]
+	})
+
+	discrepancies := []domain.StocktakeDiscrepancy{}
+
+	for _, productID := range productIDs {
+		var expected int64
+
+		if len(levels[productID]) > 0 {
+			expected = levels[productID][0].OnHand
+		}
+
+		discrepancies = append(discrepancies, domain.StocktakeDiscrepancy{
+			ProductID:  productID,
+			Expected:   expected,
+			Counted:    counted[productID],
+			Difference: counted[productID] - expected,
+		})
+	}
+
+	return discrepancies, nil
+}
+
+/*
+Every product that was counted or has had movements at the
+location of the stocktake, which are the ones it can adjust.
+*/
+func getStocktakeProductIDs(
+	runner sq.BaseRunner,
+	tenant string,
+	stocktake domain.Stocktake,
+) ([]domain.ProductId, error) {
+
+	productIDs := []domain.ProductId{}
+
+	for _, count := range stocktake.Counts {
+		productIDs = append(productIDs, count.ProductID)
+	}
+
+	rows, err := sq.Select("DISTINCT product_id").
+		From("stock_movement").
+		Where(sq.Eq{
+			"tenant":   tenant,
+			"location": stocktake.Location,
+		}).
+		RunWith(runner).
+		Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID domain.ProductId
+
+		err := rows.Scan(&productID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		productIDs = append(productIDs, productID)
+	}
+
+	return productIDs, rows.Err()
+}
+
+func (repo StocktakeRepositoryImpl) GetDiscrepancies(
+	id uint64,
+) ([]domain.StocktakeDiscrepancy, error) {
+
+	stocktake, exists, err := getStocktake(repo.DB, repo.Metadata.Tenant, id, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return []domain.StocktakeDiscrepancy{}, nil
+	}
+
+	return getDiscrepancies(repo.DB, repo.Metadata.Tenant, *stocktake)
+}
+
+/*
+The stocktake is locked while the discrepancies are turned
+into count movements so it can only ever be committed once.
+The products it can adjust are locked as well so no other
+movement changes what is on hand between reading and adjusting
+it.
+
+The transaction is READ COMMITTED on purpose. Under the
+default REPEATABLE READ the first plain read would fix the
+snapshot before the product locks are taken, and the levels
+read after waiting on the locks would miss whatever the
+writers we waited for committed. Only the locked products
+are adjusted, stock that shows up at the location while we
+commit is newer than the count and left alone.
+*/
+func (repo StocktakeRepositoryImpl) CommitStocktake(
+	id uint64,
+) ([]domain.StocktakeDiscrepancy, error) {
+
+	now := time.Now()
+
+	tx, err := repo.DB.BeginTx(context.Background(), &sql.TxOptions{
+		Isolation: sql.LevelReadCommitted,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake, exists, err := getStocktake(tx, repo.Metadata.Tenant, id, true)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if !exists || stocktake.Status != domain.StocktakeStatusOpen {
+		tx.Rollback()
+		return nil, domain.ErrStocktakeNotOpen
+	}
+
+	productIDs, err := getStocktakeProductIDs(tx, repo.Metadata.Tenant, *stocktake)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	lockedIDs, err := lockProducts(tx, repo.Metadata.Tenant, productIDs)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	locked := map[domain.ProductId]bool{}
+
+	for _, productID := range lockedIDs {
+		locked[productID] = true
+	}
+
+	discrepancies, err := getDiscrepancies(tx, repo.Metadata.Tenant, *stocktake)
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	movementInsert := sq.Insert("stock_movement").
+		Columns("tenant", "product_id", "location", "delta", "reason", "reference", "created")
+
+	adjusted := []domain.StocktakeDiscrepancy{}
+	reference := fmt.Sprintf("stocktake-%v", id)
+
+	for _, discrepancy := range discrepancies {
+		if discrepancy.Difference == 0 || !locked[discrepancy.ProductID] {
+			continue
+		}
+
+		movementInsert = movementInsert.Values(
+			repo.Metadata.Tenant,
+			discrepancy.ProductID,
+			stocktake.Location,
+			discrepancy.Difference,
+			domain.StockReasonCount,
+			reference,
+			now,
+		)
+
+		adjusted = append(adjusted, discrepancy)
+	}
+
+	if len(adjusted) > 0 {
+		_, err = movementInsert.RunWith(tx).Exec()
+
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	_, err = sq.Update("stocktake").
+		Set("status", domain.StocktakeStatusCommitted).
+		Set("committed", now).
+		Where(sq.Eq{
+			"stocktake_id": id,
+		}).
+		RunWith(tx).
+		Exec()
+
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return adjusted, nil
+}
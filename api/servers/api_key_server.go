@@ -0,0 +1,87 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+func (server Server) handleApiKeysPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var input domain.ApiKeyInput
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&input)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	created, err := server.ApiKeyService.CreateApiKey(input)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, created, http.StatusCreated)
+}
+
+/*
+Keys are listed at /api/keys and created with a POST to it.
+A DELETE to /api/keys/{id} revokes the key.
+*/
+func (server Server) handleApiKeysRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	if request.URL.Path == "/api/keys" {
+		if request.Method == "GET" {
+			keys, err := server.ApiKeyService.GetApiKeys()
+
+			if err != nil {
+				writeError(writer, getServiceErrorResponse(err))
+				return true
+			}
+
+			writeJSON(writer, keys, http.StatusOK)
+		} else if request.Method == "POST" {
+			server.handleApiKeysPOST(writer, request)
+		} else {
+			return false
+		}
+
+		return true
+	}
+
+	if request.Method != "DELETE" {
+		return false
+	}
+
+	id, err := strconv.ParseUint(path.Base(request.URL.Path), 10, 64)
+
+	if err != nil {
+		return false
+	}
+
+	err = server.ApiKeyService.RevokeApiKey(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return true
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+
+	return true
+}
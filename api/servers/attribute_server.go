@@ -0,0 +1,136 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func getAttributeNameFromPath(requestPath string) string {
+	return strings.TrimPrefix(requestPath, "/api/attributes/")
+}
+
+func (server Server) handleAttributesGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	if request.URL.Path == "/api/attributes" {
+		definitions, err := server.AttributeService.GetAttributeDefinitions()
+
+		if err != nil {
+			writeError(writer, getServiceErrorResponse(err))
+			return
+		}
+
+		writeJSON(writer, definitions, http.StatusOK)
+		return
+	}
+
+	name := getAttributeNameFromPath(request.URL.Path)
+	definition, err := server.AttributeService.GetAttributeDefinition(name)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, definition, http.StatusOK)
+}
+
+func (server Server) handleAttributesPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var definition domain.AttributeDefinition
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&definition)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.AttributeService.AddAttributeDefinition(definition)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleAttributesPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var definition domain.AttributeDefinition
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&definition)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	name := getAttributeNameFromPath(request.URL.Path)
+	err = server.AttributeService.UpdateAttributeDefinition(name, definition)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleAttributesDELETE(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	name := getAttributeNameFromPath(request.URL.Path)
+	err := server.AttributeService.DeleteAttributeDefinition(name)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleAttributesRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	isCollection := request.URL.Path == "/api/attributes"
+
+	if request.Method == "GET" {
+		server.handleAttributesGET(writer, request)
+	} else if request.Method == "POST" && isCollection {
+		server.handleAttributesPOST(writer, request)
+	} else if request.Method == "PUT" && !isCollection {
+		server.handleAttributesPUT(writer, request)
+	} else if request.Method == "DELETE" && !isCollection {
+		server.handleAttributesDELETE(writer, request)
+	} else {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,103 @@
+package servers
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiKeyScheme = "ApiKey "
+	bearerScheme = "Bearer "
+)
+
+/*
+Runs before the server dispatches anything. Every request
+has to carry either an api key or a bearer token in the
+Authorization header. The caller is recorded in the returned
+metadata, together with the tenant and roles of a token.
+*/
+func Authenticate(
+	request *http.Request,
+	metadata util.Metadata,
+	apiKeys domain.ApiKeyService,
+	tokens domain.TokenService,
+) (util.Metadata, error) {
+
+	header := request.Header.Get("Authorization")
+
+	if strings.HasPrefix(header, apiKeyScheme) {
+		key := strings.TrimSpace(strings.TrimPrefix(header, apiKeyScheme))
+
+		if key == "" {
+			return metadata, validation.GetUnauthorizedError("Missing api key in the Authorization header")
+		}
+
+		identity, roles, err := apiKeys.Authenticate(key)
+
+		if err != nil {
+			return metadata, err
+		}
+
+		metadata.Identity = identity
+		metadata.Roles = roles
+
+		return metadata, nil
+	}
+
+	if strings.HasPrefix(header, bearerScheme) {
+		token := strings.TrimSpace(strings.TrimPrefix(header, bearerScheme))
+
+		claims, err := tokens.VerifyToken(token)
+
+		if err != nil {
+			return metadata, err
+		}
+
+		/*
+			The tenant of a token can't be swapped for another
+			one with the tenant header. Only admin tokens may
+			leave the tenant out and pick one with the header.
+		*/
+		if claims.Tenant == "" && !isAdmin(claims.Roles) {
+			return metadata, validation.GetUnauthorizedError("Token is missing the tenant claim")
+		}
+
+		if claims.Tenant != "" {
+			requested := request.Header.Get(tenantHeader)
+
+			if requested != "" && requested != claims.Tenant {
+				return metadata, validation.GetUnauthorizedError(
+					fmt.Sprintf("Token is not valid for tenant (%s)", requested),
+				)
+			}
+
+			metadata.Tenant = claims.Tenant
+		}
+
+		metadata.Identity = "jwt:" + claims.Subject
+		metadata.Roles = claims.Roles
+
+		return metadata, nil
+	}
+
+	return metadata, validation.GetUnauthorizedError("Missing api key or bearer token in the Authorization header")
+}
+
+func isAdmin(roles []string) bool {
+	for _, role := range roles {
+		if role == domain.RoleAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Used for requests that are turned away before they reach a server
+func WriteServiceError(writer http.ResponseWriter, err error) {
+	writeError(writer, getServiceErrorResponse(err))
+}
@@ -0,0 +1,157 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func getBrandIDFromPath(requestPath string) (domain.BrandId, error) {
+	id, err := getProductIDFromPath(requestPath)
+
+	return domain.BrandId(id), err
+}
+
+func (server Server) handleBrandsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	if request.URL.Path == "/api/brands" {
+		brands, err := server.BrandService.GetBrands()
+
+		if err != nil {
+			writeError(writer, getServiceErrorResponse(err))
+			return
+		}
+
+		writeJSON(writer, brands, http.StatusOK)
+		return
+	}
+
+	id, err := getBrandIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing brand id"))
+		return
+	}
+
+	brand, err := server.BrandService.GetBrand(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, brand, http.StatusOK)
+}
+
+func (server Server) handleBrandsPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var brand domain.BrandInput
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&brand)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	id, err := server.BrandService.AddBrand(brand)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	idString := strconv.FormatUint(uint64(id), 10)
+	writer.WriteHeader(http.StatusCreated)
+	writer.Write([]byte(idString))
+}
+
+func (server Server) handleBrandsPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getBrandIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing brand id to update"))
+		return
+	}
+
+	var brand domain.BrandInput
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&brand)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.BrandService.UpdateBrand(id, brand)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleBrandsDELETE(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getBrandIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing brand id to delete"))
+		return
+	}
+
+	err = server.BrandService.DeleteBrand(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleBrandsRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	isCollection := request.URL.Path == "/api/brands"
+
+	if request.Method == "GET" {
+		server.handleBrandsGET(writer, request)
+	} else if request.Method == "POST" && isCollection {
+		server.handleBrandsPOST(writer, request)
+	} else if request.Method == "PUT" && !isCollection {
+		server.handleBrandsPUT(writer, request)
+	} else if request.Method == "DELETE" && !isCollection {
+		server.handleBrandsDELETE(writer, request)
+	} else {
+		return false
+	}
+
+	return true
+}
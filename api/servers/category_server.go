@@ -0,0 +1,157 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func getCategoryIDFromPath(requestPath string) (domain.CategoryId, error) {
+	id, err := getProductIDFromPath(requestPath)
+
+	return domain.CategoryId(id), err
+}
+
+func (server Server) handleCategoriesGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	if request.URL.Path == "/api/categories" {
+		categories, err := server.CategoryService.GetCategories()
+
+		if err != nil {
+			writeError(writer, getServiceErrorResponse(err))
+			return
+		}
+
+		writeJSON(writer, categories, http.StatusOK)
+		return
+	}
+
+	id, err := getCategoryIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing category id"))
+		return
+	}
+
+	category, err := server.CategoryService.GetCategory(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, category, http.StatusOK)
+}
+
+func (server Server) handleCategoriesPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var category domain.CategoryInput
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&category)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	id, err := server.CategoryService.AddCategory(category)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	idString := strconv.FormatUint(uint64(id), 10)
+	writer.WriteHeader(http.StatusCreated)
+	writer.Write([]byte(idString))
+}
+
+func (server Server) handleCategoriesPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getCategoryIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing category id to update"))
+		return
+	}
+
+	var category domain.CategoryInput
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&category)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.CategoryService.UpdateCategory(id, category)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleCategoriesDELETE(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getCategoryIDFromPath(request.URL.Path)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing category id to delete"))
+		return
+	}
+
+	err = server.CategoryService.DeleteCategory(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleCategoriesRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	isCollection := request.URL.Path == "/api/categories"
+
+	if request.Method == "GET" {
+		server.handleCategoriesGET(writer, request)
+	} else if request.Method == "POST" && isCollection {
+		server.handleCategoriesPOST(writer, request)
+	} else if request.Method == "PUT" && !isCollection {
+		server.handleCategoriesPUT(writer, request)
+	} else if request.Method == "DELETE" && !isCollection {
+		server.handleCategoriesDELETE(writer, request)
+	} else {
+		return false
+	}
+
+	return true
+}
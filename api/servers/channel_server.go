@@ -0,0 +1,190 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"path"
+)
+
+func (server Server) handleChannelsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	if request.URL.Path == "/api/channels" {
+		channels, err := server.ChannelService.GetChannels()
+
+		if err != nil {
+			writeError(writer, getServiceErrorResponse(err))
+			return
+		}
+
+		writeJSON(writer, channels, http.StatusOK)
+		return
+	}
+
+	channel, err := server.ChannelService.GetChannel(path.Base(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, channel, http.StatusOK)
+}
+
+func (server Server) handleChannelsPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var channel domain.Channel
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&channel)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.ChannelService.AddChannel(channel)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusCreated)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleChannelsPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var channel domain.Channel
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&channel)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.ChannelService.UpdateChannel(path.Base(request.URL.Path), channel)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleChannelsDELETE(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	err := server.ChannelService.DeleteChannel(path.Base(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleChannelsRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	isCollection := request.URL.Path == "/api/channels"
+
+	if request.Method == "GET" {
+		server.handleChannelsGET(writer, request)
+	} else if request.Method == "POST" && isCollection {
+		server.handleChannelsPOST(writer, request)
+	} else if request.Method == "PUT" && !isCollection {
+		server.handleChannelsPUT(writer, request)
+	} else if request.Method == "DELETE" && !isCollection {
+		server.handleChannelsDELETE(writer, request)
+	} else {
+		return false
+	}
+
+	return true
+}
+
+func (server Server) handleProductChannelsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to list channels of"))
+		return
+	}
+
+	channels, err := server.Service.GetProductChannels(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, channels, http.StatusOK)
+}
+
+/*
+The body is the complete list of channels, the product is
+taken out of every channel that is left out of it.
+*/
+func (server Server) handleProductChannelsPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to set channels of"))
+		return
+	}
+
+	var channels []domain.ProductChannel
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&channels)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.Service.SetProductChannels(id, channels)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
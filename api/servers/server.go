@@ -2,6 +2,8 @@ package servers
 
 import (
 	"api/domain"
+	"api/util"
+	"api/validation"
 	"encoding/json"
 	"net/http"
 	"path"
@@ -15,11 +17,20 @@ const (
 )
 
 type Server struct {
-	Service domain.ProductService
+	Service          domain.ProductService
+	LabelService     domain.LabelService
+	AttributeService domain.AttributeService
+	CategoryService  domain.CategoryService
+	BrandService     domain.BrandService
+	ChannelService   domain.ChannelService
+	ApiKeyService    domain.ApiKeyService
+	StockService     domain.StockService
+	StocktakeService domain.StocktakeService
 }
 
 type errorResponse struct {
 	ErrorText    string `json:"errorText"`
+	ErrorCode    string `json:"errorCode,omitempty"`
 	responseCode int
 }
 
@@ -29,9 +40,12 @@ type parsedGET struct {
 
 	start   uint64
 	num     uint64
-	sku     string
-	barcode string
+	filter  domain.ProductFilter
 	fields  []string
+	locales []string
+
+	facets       bool
+	priceBuckets []string
 }
 
 func getBadRequestResponse(text string) errorResponse {
@@ -55,6 +69,61 @@ func writeError(
 	writeJSON(writer, errorResponse, errorResponse.responseCode)
 }
 
+/*
+Errors from the service are bad requests unless the
+service tells us that something could not be found or
+that the caller isn't allowed to do it.
+*/
+func getServiceErrorResponse(err error) errorResponse {
+	_, isNotFound := err.(validation.NotFoundError)
+
+	if isNotFound {
+		return errorResponse{
+			ErrorText:    err.Error(),
+			responseCode: 404,
+		}
+	}
+
+	_, isUnauthorized := err.(validation.UnauthorizedError)
+
+	if isUnauthorized {
+		return errorResponse{
+			ErrorText:    err.Error(),
+			responseCode: 401,
+		}
+	}
+
+	forbidden, isForbidden := err.(validation.ForbiddenError)
+
+	if isForbidden {
+		return errorResponse{
+			ErrorText:    err.Error(),
+			ErrorCode:    forbidden.Code(),
+			responseCode: 403,
+		}
+	}
+
+	return getBadRequestResponse(err.Error())
+}
+
+/*
+An explicit ?locale= wins over the Accept-Language header,
+which is then only used as the fallback.
+*/
+func getLocales(request *http.Request) []string {
+	locales := []string{}
+
+	for _, locale := range strings.Split(request.URL.Query().Get("locale"), ",") {
+		locale = strings.TrimSpace(locale)
+
+		if locale != "" {
+			locales = append(locales, locale)
+		}
+	}
+
+	return append(locales, util.ParseAcceptLanguage(request.Header.Get("Accept-Language"))...)
+}
+
 func writeJSON(
 	writer http.ResponseWriter,
 	item interface{},
@@ -117,9 +186,41 @@ func parseGET(request *http.Request) parsedGET {
 			parsed.num = num
 		}
 
-		parsed.sku = query.Get("sku")
-		parsed.barcode = query.Get("barcode")
+		parsed.filter.Sku = query.Get("sku")
+		parsed.filter.Barcode = query.Get("barcode")
+
+		category, categoryErr := strconv.ParseUint(query.Get("category"), 10, 32)
+
+		if categoryErr == nil {
+			categoryID := domain.CategoryId(category)
+			parsed.filter.Category = &categoryID
+			parsed.filter.IncludeSubcategories = query.Get("includeSubcategories") == "true"
+		}
+
+		brand, brandErr := strconv.ParseUint(query.Get("brand"), 10, 32)
+
+		if brandErr == nil {
+			brandID := domain.BrandId(brand)
+			parsed.filter.BrandID = &brandID
+		}
+
+		delimitedStatuses := query.Get("status")
+
+		if delimitedStatuses != "" {
+			parsed.filter.Statuses = strings.Split(delimitedStatuses, ",")
+		}
+
+		parsed.filter.Channel = query.Get("channel")
+
 		parsed.getType = multipleGET
+
+		parsed.facets = query.Get("facets") == "true"
+
+		delimitedBuckets := query.Get("priceBuckets")
+
+		if delimitedBuckets != "" {
+			parsed.priceBuckets = strings.Split(delimitedBuckets, ",")
+		}
 	}
 
 	delimitedFields := query.Get("fields")
@@ -128,6 +229,8 @@ func parseGET(request *http.Request) parsedGET {
 		parsed.fields = strings.Split(delimitedFields, ",")
 	}
 
+	parsed.locales = getLocales(request)
+
 	return parsed
 }
 
@@ -139,10 +242,14 @@ func (server Server) handleGET(
 	parsed := parseGET(request)
 
 	if parsed.getType == singleGET {
-		product, error := server.Service.GetProduct(parsed.productID, parsed.fields)
+		product, error := server.Service.GetProduct(
+			parsed.productID,
+			parsed.fields,
+			parsed.locales,
+		)
 
 		if error != nil {
-			writeError(writer, getBadRequestResponse(error.Error()))
+			writeError(writer, getServiceErrorResponse(error))
 		} else {
 			writeJSON(writer, product, http.StatusOK)
 		}
@@ -150,27 +257,203 @@ func (server Server) handleGET(
 		products, count, error := server.Service.GetProducts(
 			parsed.start,
 			parsed.num,
-			parsed.sku,
-			parsed.barcode,
+			parsed.filter,
 			parsed.fields,
+			parsed.locales,
 		)
 
 		if error != nil {
-			writeError(writer, getBadRequestResponse(error.Error()))
-		} else {
-			envelope := struct {
-				TotalCount uint32           `json:"totalCount"`
-				Items      []domain.Product `json:"items"`
-			}{
-				TotalCount: count,
-				Items:      products,
-			}
+			writeError(writer, getServiceErrorResponse(error))
+			return
+		}
+
+		envelope := struct {
+			TotalCount uint32                `json:"totalCount"`
+			Items      []domain.Product      `json:"items"`
+			Facets     *domain.ProductFacets `json:"facets,omitempty"`
+		}{
+			TotalCount: count,
+			Items:      products,
+		}
 
-			writeJSON(writer, envelope, http.StatusOK)
+		if parsed.facets {
+			envelope.Facets, error = server.Service.GetProductFacets(
+				parsed.filter,
+				parsed.priceBuckets,
+			)
+
+			if error != nil {
+				writeError(writer, getBadRequestResponse(error.Error()))
+				return
+			}
 		}
+
+		writeJSON(writer, envelope, http.StatusOK)
 	}
 }
 
+/*
+Variants are listed at /api/products/{id}/variants and take
+the same start, num and fields parameters as the product
+listing.
+*/
+func (server Server) handleVariantsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to list variants of"))
+		return
+	}
+
+	parsed := parseGET(request)
+
+	products, count, err := server.Service.GetVariants(
+		id,
+		parsed.start,
+		parsed.num,
+		parsed.fields,
+		parsed.locales,
+	)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	envelope := struct {
+		TotalCount uint32           `json:"totalCount"`
+		Items      []domain.Product `json:"items"`
+	}{
+		TotalCount: count,
+		Items:      products,
+	}
+
+	writeJSON(writer, envelope, http.StatusOK)
+}
+
+func (server Server) handleRelationsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to list relations of"))
+		return
+	}
+
+	relations, err := server.Service.GetProductRelations(
+		id,
+		request.URL.Query().Get("type"),
+		getLocales(request),
+	)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, relations, http.StatusOK)
+}
+
+/*
+The body is the complete list of relations, anything that
+is left out of it is removed from the product.
+*/
+func (server Server) handleRelationsPUT(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to set relations of"))
+		return
+	}
+
+	var relations []domain.ProductRelation
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&relations)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	err = server.Service.SetProductRelations(id, relations)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write([]byte("true"))
+}
+
+func (server Server) handleSuggest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	query := request.URL.Query()
+
+	num, err := strconv.ParseUint(query.Get("num"), 10, 64)
+
+	if err != nil {
+		num = 0
+	}
+
+	suggestions, err := server.Service.SuggestProducts(query.Get("prefix"), num)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, suggestions, http.StatusOK)
+}
+
+func (server Server) handleBarcodeGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	query := request.URL.Query()
+	barcode := strings.TrimPrefix(request.URL.Path, "/api/barcodes/")
+
+	var fields []string
+
+	delimitedFields := query.Get("fields")
+
+	if delimitedFields != "" {
+		fields = strings.Split(delimitedFields, ",")
+	}
+
+	product, err := server.Service.GetProductByBarcode(
+		barcode,
+		query.Get("normalize") == "true",
+		fields,
+		getLocales(request),
+	)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, product, http.StatusOK)
+}
+
 func (server Server) handlePOST(
 	writer http.ResponseWriter,
 	request *http.Request,
@@ -192,7 +475,7 @@ func (server Server) handlePOST(
 	id, err = server.Service.AddProduct(product)
 
 	if err != nil {
-		writeError(writer, getBadRequestResponse(err.Error()))
+		writeError(writer, getServiceErrorResponse(err))
 		return
 	}
 
@@ -201,6 +484,140 @@ func (server Server) handlePOST(
 	writer.Write([]byte(idString))
 }
 
+func (server Server) handleGenerateBarcode(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(request.URL.Path))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to generate barcode for"))
+		return
+	}
+
+	barcode, err := server.Service.GenerateBarcode(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, barcode, http.StatusCreated)
+}
+
+/*
+Barcode images live under /api/products/{id}/barcodes/ with
+the barcode as the file name and the image format as the
+file extension, for example .../barcodes/4006381333931.svg
+*/
+func parseBarcodeImageRequest(
+	request *http.Request,
+) (domain.ProductId, string, domain.BarcodeImageOptions, error) {
+
+	options := domain.BarcodeImageOptions{}
+
+	barcodesDir, file := path.Split(request.URL.Path)
+	extension := path.Ext(file)
+
+	id, err := getProductIDFromPath(path.Dir(path.Clean(barcodesDir)))
+
+	if err != nil {
+		return 0, "", options, err
+	}
+
+	query := request.URL.Query()
+
+	options.Format = strings.TrimPrefix(extension, ".")
+	options.Symbology = query.Get("symbology")
+
+	if query.Get("moduleSize") != "" {
+		options.ModuleSize, err = strconv.Atoi(query.Get("moduleSize"))
+
+		if err != nil {
+			return 0, "", options, err
+		}
+	}
+
+	if query.Get("height") != "" {
+		options.BarHeight, err = strconv.Atoi(query.Get("height"))
+
+		if err != nil {
+			return 0, "", options, err
+		}
+	}
+
+	if query.Get("quietZone") != "" {
+		quietZone, err := strconv.Atoi(query.Get("quietZone"))
+
+		if err != nil {
+			return 0, "", options, err
+		}
+
+		options.QuietZone = &quietZone
+	}
+
+	return id, strings.TrimSuffix(file, extension), options, nil
+}
+
+func (server Server) handleBarcodeImage(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, barcode, options, err := parseBarcodeImageRequest(request)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	image, err := server.Service.GetBarcodeImage(id, barcode, options)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	if options.Format == "svg" {
+		writer.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		writer.Header().Set("Content-Type", "image/png")
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(image)
+}
+
+func (server Server) handleLabelsPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	var input domain.LabelInput
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&input)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	pdf, err := server.LabelService.GetLabelsPDF(input)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/pdf")
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(pdf)
+}
+
 func (server Server) handlePUT(
 	writer http.ResponseWriter,
 	request *http.Request,
@@ -228,7 +645,7 @@ func (server Server) handlePUT(
 	err = server.Service.UpdateProduct(id, changes)
 
 	if err != nil {
-		writeError(writer, getBadRequestResponse(err.Error()))
+		writeError(writer, getServiceErrorResponse(err))
 	} else {
 		writer.WriteHeader(http.StatusOK)
 		writer.Write([]byte("true"))
@@ -251,7 +668,7 @@ func (server Server) handleDELETE(
 	err = server.Service.DeleteProduct(id)
 
 	if err != nil {
-		writeError(writer, getBadRequestResponse(err.Error()))
+		writeError(writer, getServiceErrorResponse(err))
 		return
 	}
 
@@ -269,9 +686,97 @@ func (server Server) HandleRequest(
 
 	path := request.URL.Path
 
-	if strings.HasPrefix(path, "/api/products") {
+	if path == "/api/products/suggest" {
+
+		if request.Method == "GET" {
+			server.handleSuggest(writer, request)
+		} else {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/attributes" || strings.HasPrefix(path, "/api/attributes/") {
+
+		if !server.handleAttributesRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/categories" || strings.HasPrefix(path, "/api/categories/") {
+
+		if !server.handleCategoriesRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/brands" || strings.HasPrefix(path, "/api/brands/") {
+
+		if !server.handleBrandsRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/keys" || strings.HasPrefix(path, "/api/keys/") {
+
+		if !server.handleApiKeysRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/channels" || strings.HasPrefix(path, "/api/channels/") {
+
+		if !server.handleChannelsRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if strings.HasPrefix(path, "/api/stock/reservations/") {
+
+		if !server.handleReservationsRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/stocktakes" || strings.HasPrefix(path, "/api/stocktakes/") {
+
+		if !server.handleStocktakesRequest(writer, request) {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if path == "/api/labels" {
+
+		if request.Method == "POST" {
+			server.handleLabelsPOST(writer, request)
+		} else {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if strings.HasPrefix(path, "/api/barcodes/") {
 
 		if request.Method == "GET" {
+			server.handleBarcodeGET(writer, request)
+		} else {
+			notFoundError = getNotFoundResponse()
+		}
+
+	} else if strings.HasPrefix(path, "/api/products") {
+
+		if request.Method == "POST" && strings.HasSuffix(path, "/barcodes:generate") {
+			server.handleGenerateBarcode(writer, request)
+		} else if request.Method == "GET" && strings.Contains(path, "/barcodes/") {
+			server.handleBarcodeImage(writer, request)
+		} else if request.Method == "GET" && strings.HasSuffix(path, "/variants") {
+			server.handleVariantsGET(writer, request)
+		} else if request.Method == "GET" && strings.HasSuffix(path, "/relations") {
+			server.handleRelationsGET(writer, request)
+		} else if request.Method == "PUT" && strings.HasSuffix(path, "/relations") {
+			server.handleRelationsPUT(writer, request)
+		} else if request.Method == "GET" && strings.HasSuffix(path, "/channels") {
+			server.handleProductChannelsGET(writer, request)
+		} else if request.Method == "PUT" && strings.HasSuffix(path, "/channels") {
+			server.handleProductChannelsPUT(writer, request)
+		} else if request.Method == "POST" && strings.HasSuffix(path, "/stock/reservations") {
+			server.handleReservationPOST(writer, request)
+		} else if request.Method == "GET" && strings.HasSuffix(path, "/stock/movements") {
+			server.handleStockMovementsGET(writer, request)
+		} else if request.Method == "GET" && strings.HasSuffix(path, "/stock") {
+			server.handleStockGET(writer, request)
+		} else if request.Method == "POST" && strings.HasSuffix(path, "/stock") {
+			server.handleStockPOST(writer, request)
+		} else if request.Method == "GET" {
 			server.handleGET(writer, request)
 		} else if request.Method == "POST" {
 			server.handlePOST(writer, request)
@@ -0,0 +1,205 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Stock lives under /api/products/{id}/stock
+func getStockProductID(request *http.Request) (domain.ProductId, error) {
+	return getProductIDFromPath(path.Dir(request.URL.Path))
+}
+
+func (server Server) handleStockGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getStockProductID(request)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to read stock of"))
+		return
+	}
+
+	levels, err := server.StockService.GetStock(id)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, levels, http.StatusOK)
+}
+
+func (server Server) handleStockPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getStockProductID(request)
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to adjust stock of"))
+		return
+	}
+
+	var adjustment domain.StockAdjustment
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&adjustment)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	levels, err := server.StockService.AdjustStock(id, adjustment)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, levels, http.StatusOK)
+}
+
+/*
+The ledger lives at /api/products/{id}/stock/movements and
+can be narrowed down to one location with ?location=
+*/
+func (server Server) handleStockMovementsGET(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(path.Dir(request.URL.Path)))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to read stock movements of"))
+		return
+	}
+
+	query := request.URL.Query()
+
+	start, err := strconv.ParseUint(query.Get("start"), 10, 64)
+
+	if err != nil {
+		start = 0
+	}
+
+	num, err := strconv.ParseUint(query.Get("num"), 10, 64)
+
+	if err != nil {
+		num = 0
+	}
+
+	movements, count, err := server.StockService.GetStockMovements(
+		id,
+		query.Get("location"),
+		start,
+		num,
+	)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	envelope := struct {
+		TotalCount uint32                 `json:"totalCount"`
+		Items      []domain.StockMovement `json:"items"`
+	}{
+		TotalCount: count,
+		Items:      movements,
+	}
+
+	writeJSON(writer, envelope, http.StatusOK)
+}
+
+func (server Server) handleReservationPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	id, err := getProductIDFromPath(path.Dir(path.Dir(request.URL.Path)))
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse("Missing product id to reserve stock of"))
+		return
+	}
+
+	var input domain.StockReservationInput
+
+	decoder := json.NewDecoder(request.Body)
+	err = decoder.Decode(&input)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	reservation, err := server.StockService.ReserveStock(id, input)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, reservation, http.StatusCreated)
+}
+
+/*
+Reservations are looked up at /api/stock/reservations/{id}
+and finished with a POST to .../{id}:confirm or .../{id}:release
+*/
+func (server Server) handleReservationsRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	base := path.Base(request.URL.Path)
+	action := ""
+
+	separator := strings.LastIndex(base, ":")
+
+	if separator >= 0 {
+		action = base[separator+1:]
+		base = base[:separator]
+	}
+
+	id, err := strconv.ParseUint(base, 10, 64)
+
+	if err != nil {
+		return false
+	}
+
+	var reservation *domain.StockReservation
+
+	if request.Method == "GET" && action == "" {
+		reservation, err = server.StockService.GetReservation(id)
+	} else if request.Method == "POST" && action == "confirm" {
+		reservation, err = server.StockService.ConfirmReservation(id)
+	} else if request.Method == "POST" && action == "release" {
+		reservation, err = server.StockService.ReleaseReservation(id)
+	} else {
+		return false
+	}
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return true
+	}
+
+	writeJSON(writer, reservation, http.StatusOK)
+
+	return true
+}
@@ -0,0 +1,145 @@
+package servers
+
+import (
+	"api/domain"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+func (server Server) handleStocktakePOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+
+	input := struct {
+		Location string `json:"location"`
+	}{}
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&input)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	id, err := server.StocktakeService.OpenStocktake(input.Location)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, id, http.StatusCreated)
+}
+
+func (server Server) handleStocktakeCountsPOST(
+	writer http.ResponseWriter,
+	request *http.Request,
+	id uint64,
+) {
+
+	var scans []domain.StocktakeScan
+
+	decoder := json.NewDecoder(request.Body)
+	err := decoder.Decode(&scans)
+
+	defer request.Body.Close()
+
+	if err != nil {
+		writeError(writer, getBadRequestResponse(err.Error()))
+		return
+	}
+
+	stocktake, err := server.StocktakeService.AddStocktakeScans(id, scans)
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return
+	}
+
+	writeJSON(writer, stocktake, http.StatusOK)
+}
+
+/*
+Stocktakes are opened with a POST to /api/stocktakes and then
+live at /api/stocktakes/{id} with the scans posted to .../counts,
+the preview at .../discrepancies and a POST to .../{id}:commit
+*/
+func (server Server) handleStocktakesRequest(
+	writer http.ResponseWriter,
+	request *http.Request,
+) bool {
+
+	urlPath := request.URL.Path
+
+	if urlPath == "/api/stocktakes" {
+		if request.Method != "POST" {
+			return false
+		}
+
+		server.handleStocktakePOST(writer, request)
+		return true
+	}
+
+	parts := strings.Split(strings.TrimPrefix(urlPath, "/api/stocktakes/"), "/")
+
+	if len(parts) > 2 {
+		return false
+	}
+
+	base := parts[0]
+	action := ""
+
+	separator := strings.LastIndex(base, ":")
+
+	if separator >= 0 {
+		action = base[separator+1:]
+		base = base[:separator]
+	}
+
+	id, err := strconv.ParseUint(base, 10, 64)
+
+	if err != nil {
+		return false
+	}
+
+	sub := ""
+
+	if len(parts) == 2 {
+		if action != "" {
+			return false
+		}
+
+		sub = path.Clean(parts[1])
+	}
+
+	var result interface{}
+
+	if request.Method == "GET" && sub == "" && action == "" {
+		result, err = server.StocktakeService.GetStocktake(id)
+	} else if request.Method == "POST" && sub == "counts" {
+		server.handleStocktakeCountsPOST(writer, request, id)
+		return true
+	} else if request.Method == "GET" && sub == "discrepancies" {
+		result, err = server.StocktakeService.GetStocktakeDiscrepancies(id)
+	} else if request.Method == "POST" && action == "commit" {
+		result, err = server.StocktakeService.CommitStocktake(id)
+	} else {
+		return false
+	}
+
+	if err != nil {
+		writeError(writer, getServiceErrorResponse(err))
+		return true
+	}
+
+	writeJSON(writer, result, http.StatusOK)
+
+	return true
+}
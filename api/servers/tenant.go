@@ -0,0 +1,36 @@
+package servers
+
+import (
+	"api/util"
+	"api/validation"
+	"net/http"
+)
+
+const tenantHeader = "X-Tenant"
+
+/*
+The tenant is resolved before anything else since every
+repository of the request is scoped to it. Requests without
+the header keep working against the default tenant.
+*/
+func ResolveTenant(request *http.Request) (string, error) {
+
+	tenant := request.Header.Get(tenantHeader)
+
+	if tenant == "" {
+		return util.DefaultTenant, nil
+	}
+
+	err := validation.ValidateTenant(tenant)
+
+	if err != nil {
+		return "", err
+	}
+
+	return tenant, nil
+}
+
+// Used for requests that are turned away before they reach a server
+func WriteBadRequest(writer http.ResponseWriter, text string) {
+	writeError(writer, getBadRequestResponse(text))
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+)
+
+// The part of a key that is kept around to recognise it by
+const apiKeyPrefixLength = 11
+
+type ApiKeyServiceImpl struct {
+	Repo     domain.ApiKeyRepository
+	Config   util.Config
+	Metadata util.Metadata
+}
+
+func (service ApiKeyServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service ApiKeyServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+/*
+Keys are random enough that a plain sha256 is all that is
+needed, there is nothing to guess that a slow hash would
+protect against.
+*/
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (service ApiKeyServiceImpl) GetApiKeys() ([]domain.ApiKey, error) {
+
+	service.log("Requesting api keys")
+
+	err := authorizeAdmin(service.Metadata, "Only admins can list api keys")
+
+	if err != nil {
+		service.log("Not allowed to list api keys")
+
+		return nil, err
+	}
+
+	keys, err := service.Repo.GetApiKeys()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return keys, nil
+}
+
+func (service ApiKeyServiceImpl) CreateApiKey(
+	input domain.ApiKeyInput,
+) (*domain.CreatedApiKey, error) {
+
+	service.log("Creating api key (%s)", input.Name)
+
+	err := authorizeAdmin(service.Metadata, "Only admins can create api keys")
+
+	if err != nil {
+		service.log("Not allowed to create api keys")
+
+		return nil, err
+	}
+
+	err = validation.ValidateApiKey(input)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	secret := make([]byte, 24)
+
+	_, err = rand.Read(secret)
+
+	if err != nil {
+		service.log("Can't generate api key %s", err.Error())
+
+		return nil, fmt.Errorf("Can't generate api key")
+	}
+
+	key := "pk_" + hex.EncodeToString(secret)
+	prefix := key[:apiKeyPrefixLength]
+
+	if len(input.Roles) == 0 {
+		input.Roles = []string{domain.RoleViewer}
+	}
+
+	id, err := service.Repo.AddApiKey(input, prefix, hashApiKey(key))
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	created, _, err := service.Repo.GetApiKey(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Created api key %v", id)
+
+	return &domain.CreatedApiKey{
+		ApiKey: *created,
+		Key:    key,
+	}, nil
+}
+
+func (service ApiKeyServiceImpl) RevokeApiKey(
+	id uint64,
+) error {
+
+	service.log("Revoking api key %v", id)
+
+	err := authorizeAdmin(service.Metadata, "Only admins can revoke api keys")
+
+	if err != nil {
+		service.log("Not allowed to revoke api keys")
+
+		return err
+	}
+
+	key, exists, err := service.Repo.GetApiKey(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find api key %v", id)
+
+		return validation.GetApiKeyNotFoundError(id)
+	}
+
+	if key.Revoked != nil {
+		service.log("Api key %v is already revoked", id)
+
+		return nil
+	}
+
+	err = service.Repo.RevokeApiKey(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Revoked api key")
+
+	return nil
+}
+
+/*
+The admin key from the config is checked first so it keeps
+working even when the tenant has no keys of its own yet.
+*/
+func (service ApiKeyServiceImpl) Authenticate(
+	key string,
+) (string, []string, error) {
+
+	adminKey := service.Config.AdminApiKey
+
+	if adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+		return "apikey:admin", []string{domain.RoleAdmin}, nil
+	}
+
+	apiKey, exists, err := service.Repo.GetApiKeyByHash(hashApiKey(key))
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return "", nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists || apiKey.Revoked != nil {
+		service.log("Unknown or revoked api key")
+
+		return "", nil, validation.GetUnauthorizedError("Invalid api key")
+	}
+
+	return fmt.Sprintf("apikey:%v", apiKey.KeyID), apiKey.Roles, nil
+}
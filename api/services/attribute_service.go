@@ -0,0 +1,211 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"strings"
+)
+
+type AttributeServiceImpl struct {
+	Repo     domain.AttributeRepository
+	Metadata util.Metadata
+}
+
+func (service AttributeServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service AttributeServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+func (service AttributeServiceImpl) GetAttributeDefinitions() ([]domain.AttributeDefinition, error) {
+
+	service.log("Requesting attribute definitions")
+
+	definitions, err := service.Repo.GetAttributeDefinitions()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return definitions, nil
+}
+
+func (service AttributeServiceImpl) GetAttributeDefinition(
+	name string,
+) (*domain.AttributeDefinition, error) {
+
+	service.log("Requesting attribute definition (%s)", name)
+
+	definition, exists, err := service.Repo.GetAttributeDefinition(name)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find attribute definition (%s)", name)
+
+		return nil, validation.GetAttributeDefinitionNotFoundError(name)
+	}
+
+	return definition, nil
+}
+
+/*
+Names are compared without case so that we never end up
+with both a "Color" and a "color" definition.
+*/
+func (service AttributeServiceImpl) AddAttributeDefinition(
+	definition domain.AttributeDefinition,
+) error {
+
+	service.log("Adding attribute definition (%s)", definition.Name)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change attribute definitions")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	err = validation.ValidateAttributeDefinition(definition)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	definitions, err := service.Repo.GetAttributeDefinitions()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	for _, existing := range definitions {
+		if strings.EqualFold(existing.Name, definition.Name) {
+			service.log("Attribute definition already exists")
+
+			return validation.GetAttributeDefinitionExistsError(existing.Name)
+		}
+	}
+
+	err = service.Repo.AddAttributeDefinition(definition)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Added attribute definition")
+
+	return nil
+}
+
+/*
+The name of a definition can not be changed since products
+refer to it by name. Existing product attributes are not
+checked again, the new definition only applies to products
+that are added or updated from now on.
+*/
+func (service AttributeServiceImpl) UpdateAttributeDefinition(
+	name string,
+	definition domain.AttributeDefinition,
+) error {
+
+	service.log("Updating attribute definition (%s)", name)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change attribute definitions")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	existing, exists, err := service.Repo.GetAttributeDefinition(name)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find attribute definition")
+
+		return validation.GetAttributeDefinitionNotFoundError(name)
+	}
+
+	definition.Name = existing.Name
+
+	err = validation.ValidateAttributeDefinition(definition)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	err = service.Repo.UpdateAttributeDefinition(existing.Name, definition)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Updated attribute definition")
+
+	return nil
+}
+
+func (service AttributeServiceImpl) DeleteAttributeDefinition(
+	name string,
+) error {
+
+	service.log("Deleting attribute definition (%s)", name)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change attribute definitions")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	existing, exists, err := service.Repo.GetAttributeDefinition(name)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find attribute definition")
+
+		return validation.GetAttributeDefinitionNotFoundError(name)
+	}
+
+	err = service.Repo.DeleteAttributeDefinition(existing.Name)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Deleted attribute definition")
+
+	return nil
+}
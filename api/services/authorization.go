@@ -0,0 +1,134 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"reflect"
+)
+
+/*
+Admins are allowed to do everything so they pass every
+check. A caller without any roles can only read.
+*/
+func hasRole(metadata util.Metadata, allowed ...string) bool {
+	for _, role := range metadata.Roles {
+		if role == domain.RoleAdmin {
+			return true
+		}
+
+		for _, candidate := range allowed {
+			if role == candidate {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func authorizeAdmin(metadata util.Metadata, message string) error {
+	if !hasRole(metadata) {
+		return validation.GetForbiddenError(validation.ErrorCodeAdmin, message)
+	}
+
+	return nil
+}
+
+/*
+Everything that changes the catalogue or the stock takes an
+editor, viewers can only read.
+*/
+func authorizeEditor(metadata util.Metadata, message string) error {
+	if !hasRole(metadata, domain.RoleEditor) {
+		return validation.GetForbiddenError(validation.ErrorCodeEditor, message)
+	}
+
+	return nil
+}
+
+func (service ProductServiceImpl) authorizeEdit() error {
+	err := authorizeEditor(service.Metadata, "Only editors can change products")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+	}
+
+	return err
+}
+
+/*
+Price managers can change the price without being editors
+and editors can change everything else, but not the price.
+*/
+func (service ProductServiceImpl) authorizeUpdate(product domain.ProductUpdateInput) error {
+
+	if product.Price != nil {
+		err := service.authorizePrice()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	withoutPrice := product
+	withoutPrice.Price = nil
+
+	if reflect.DeepEqual(withoutPrice, domain.ProductUpdateInput{}) {
+		return nil
+	}
+
+	return service.authorizeEdit()
+}
+
+func (service ProductServiceImpl) authorizePrice() error {
+	if !hasRole(service.Metadata, domain.RolePriceManager) {
+		service.log("Caller (%s) is not a price manager", service.Metadata.Identity)
+
+		return validation.GetForbiddenError(
+			validation.ErrorCodePriceManager,
+			"Only price managers can change the price of a product",
+		)
+	}
+
+	return nil
+}
+
+/*
+A channel price is what customers of that channel pay, so
+setting one or dropping one takes a price manager, the same
+as the product price does. The caller checks for an editor.
+*/
+func (service ProductServiceImpl) authorizeChannels(
+	current []domain.ProductChannel,
+	channels []domain.ProductChannel,
+) error {
+
+	prices := map[string]*string{}
+
+	for _, channel := range channels {
+		if channel.Price != nil {
+			return service.authorizePrice()
+		}
+
+		prices[channel.Channel] = channel.Price
+	}
+
+	for _, channel := range current {
+		if channel.Price != nil && prices[channel.Channel] == nil {
+			return service.authorizePrice()
+		}
+	}
+
+	return nil
+}
+
+func (service ProductServiceImpl) authorizeDelete() error {
+	err := authorizeAdmin(service.Metadata, "Only admins can delete products")
+
+	if err != nil {
+		service.log("Caller (%s) is not an admin", service.Metadata.Identity)
+	}
+
+	return err
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"fmt"
+	"strings"
+)
+
+type BrandServiceImpl struct {
+	Repo     domain.BrandRepository
+	Metadata util.Metadata
+}
+
+func (service BrandServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service BrandServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+/*
+Brand names are compared without case, the same way as
+attribute definitions, so "ACME" and "Acme" can't both exist.
+*/
+func (service BrandServiceImpl) validateUniqueName(
+	id domain.BrandId,
+	name string,
+) error {
+
+	brands, err := service.Repo.GetBrands()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	for _, existing := range brands {
+		if existing.BrandID != id && strings.EqualFold(existing.Name, name) {
+			service.log("Brand already exists")
+
+			return validation.GetBrandExistsError(existing.Name)
+		}
+	}
+
+	return nil
+}
+
+func (service BrandServiceImpl) GetBrands() ([]domain.Brand, error) {
+
+	service.log("Requesting brands")
+
+	brands, err := service.Repo.GetBrands()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return brands, nil
+}
+
+func (service BrandServiceImpl) GetBrand(
+	id domain.BrandId,
+) (*domain.Brand, error) {
+
+	service.log("Requesting brand %v", id)
+
+	brand, exists, err := service.Repo.GetBrand(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find brand %v", id)
+
+		return nil, validation.GetBrandNotFoundError(id)
+	}
+
+	return brand, nil
+}
+
+func (service BrandServiceImpl) AddBrand(
+	brand domain.BrandInput,
+) (domain.BrandId, error) {
+
+	service.log("Adding brand (%s)", brand.Name)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change brands")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return 0, err
+	}
+
+	err = validation.ValidateBrand(brand)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return 0, err
+	}
+
+	err = service.validateUniqueName(0, brand.Name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := service.Repo.AddBrand(brand)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return 0, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Added brand %v", id)
+
+	return id, nil
+}
+
+func (service BrandServiceImpl) UpdateBrand(
+	id domain.BrandId,
+	brand domain.BrandInput,
+) error {
+
+	service.log("Updating brand %v", id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change brands")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	err = validation.ValidateBrand(brand)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	_, exists, err := service.Repo.GetBrand(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find brand %v", id)
+
+		return validation.GetBrandNotFoundError(id)
+	}
+
+	err = service.validateUniqueName(id, brand.Name)
+
+	if err != nil {
+		return err
+	}
+
+	err = service.Repo.UpdateBrand(id, brand)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Updated brand")
+
+	return nil
+}
+
+/*
+Products refer to their brand by id so a brand can only be
+deleted once no product uses it anymore.
+*/
+func (service BrandServiceImpl) DeleteBrand(
+	id domain.BrandId,
+) error {
+
+	service.log("Deleting brand %v", id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change brands")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	_, exists, err := service.Repo.GetBrand(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find brand %v", id)
+
+		return validation.GetBrandNotFoundError(id)
+	}
+
+	count, err := service.Repo.CountBrandProducts(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if count > 0 {
+		service.log("Brand is still in use")
+
+		return fmt.Errorf("Brand %v is still used by %v products", id, count)
+	}
+
+	err = service.Repo.DeleteBrand(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Deleted brand")
+
+	return nil
+}
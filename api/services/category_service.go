@@ -0,0 +1,309 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"errors"
+	"fmt"
+)
+
+type CategoryServiceImpl struct {
+	Repo     domain.CategoryRepository
+	Metadata util.Metadata
+}
+
+func (service CategoryServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service CategoryServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+/*
+Returns the category itself followed by every category
+below it in the tree. The categories are walked breadth
+first so the closest subcategories come first.
+*/
+func getCategoryWithSubcategories(
+	categories []domain.Category,
+	id domain.CategoryId,
+) []domain.CategoryId {
+
+	children := map[domain.CategoryId][]domain.CategoryId{}
+
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.CategoryID)
+		}
+	}
+
+	result := []domain.CategoryId{id}
+	visited := map[domain.CategoryId]struct{}{id: struct{}{}}
+
+	for i := 0; i < len(result); i++ {
+		for _, child := range children[result[i]] {
+			_, seen := visited[child]
+
+			if !seen {
+				visited[child] = struct{}{}
+				result = append(result, child)
+			}
+		}
+	}
+
+	return result
+}
+
+/*
+Moving a category below itself or below one of its own
+subcategories would cut that part of the tree off from
+the roots, so we walk up from the new parent and make sure
+we never run into the category being moved.
+*/
+func validateCategoryParent(
+	categories []domain.Category,
+	id domain.CategoryId,
+	parentID *domain.CategoryId,
+) error {
+
+	if parentID == nil {
+		return nil
+	}
+
+	parents := map[domain.CategoryId]*domain.CategoryId{}
+
+	for _, category := range categories {
+		parents[category.CategoryID] = category.ParentID
+	}
+
+	_, exists := parents[*parentID]
+
+	if !exists {
+		return fmt.Errorf("Can't find parent category %v", *parentID)
+	}
+
+	current := parentID
+
+	for steps := 0; current != nil && steps <= len(categories); steps++ {
+		if *current == id {
+			return errors.New("A category can't be moved below itself or one of its subcategories")
+		}
+
+		current = parents[*current]
+	}
+
+	return nil
+}
+
+func (service CategoryServiceImpl) GetCategories() ([]domain.Category, error) {
+
+	service.log("Requesting categories")
+
+	categories, err := service.Repo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return categories, nil
+}
+
+func (service CategoryServiceImpl) GetCategory(
+	id domain.CategoryId,
+) (*domain.Category, error) {
+
+	service.log("Requesting category %v", id)
+
+	category, exists, err := service.Repo.GetCategory(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find category %v", id)
+
+		return nil, validation.GetCategoryNotFoundError(id)
+	}
+
+	return category, nil
+}
+
+func (service CategoryServiceImpl) AddCategory(
+	category domain.CategoryInput,
+) (domain.CategoryId, error) {
+
+	service.log("Adding category (%s)", category.Name)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change categories")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return 0, err
+	}
+
+	err = validation.ValidateCategory(category)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return 0, err
+	}
+
+	categories, err := service.Repo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return 0, validation.GetGenericDatabaseError()
+	}
+
+	err = validateCategoryParent(categories, 0, category.ParentID)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return 0, err
+	}
+
+	id, err := service.Repo.AddCategory(category)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return 0, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Added category %v", id)
+
+	return id, nil
+}
+
+func (service CategoryServiceImpl) UpdateCategory(
+	id domain.CategoryId,
+	category domain.CategoryInput,
+) error {
+
+	service.log("Updating category %v", id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change categories")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	err = validation.ValidateCategory(category)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	_, exists, err := service.Repo.GetCategory(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find category %v", id)
+
+		return validation.GetCategoryNotFoundError(id)
+	}
+
+	categories, err := service.Repo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	err = validateCategoryParent(categories, id, category.ParentID)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	err = service.Repo.UpdateCategory(id, category)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Updated category")
+
+	return nil
+}
+
+/*
+Only leaf categories can be deleted, otherwise we would
+have to decide where their subcategories should end up.
+*/
+func (service CategoryServiceImpl) DeleteCategory(
+	id domain.CategoryId,
+) error {
+
+	service.log("Deleting category %v", id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change categories")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	categories, err := service.Repo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	exists := false
+
+	for _, category := range categories {
+		if category.CategoryID == id {
+			exists = true
+		}
+
+		if category.ParentID != nil && *category.ParentID == id {
+			service.log("Category still has subcategories")
+
+			return fmt.Errorf("Category %v still has subcategories", id)
+		}
+	}
+
+	if !exists {
+		service.log("Can't find category %v", id)
+
+		return validation.GetCategoryNotFoundError(id)
+	}
+
+	err = service.Repo.DeleteCategory(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Deleted category")
+
+	return nil
+}
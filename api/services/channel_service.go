@@ -0,0 +1,203 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"fmt"
+)
+
+type ChannelServiceImpl struct {
+	Repo     domain.ChannelRepository
+	Metadata util.Metadata
+}
+
+func (service ChannelServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service ChannelServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+func (service ChannelServiceImpl) GetChannels() ([]domain.Channel, error) {
+
+	service.log("Requesting channels")
+
+	channels, err := service.Repo.GetChannels()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return channels, nil
+}
+
+func (service ChannelServiceImpl) GetChannel(
+	code string,
+) (*domain.Channel, error) {
+
+	service.log("Requesting channel (%s)", code)
+
+	channel, exists, err := service.Repo.GetChannel(code)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find channel (%s)", code)
+
+		return nil, validation.GetChannelNotFoundError(code)
+	}
+
+	return channel, nil
+}
+
+func (service ChannelServiceImpl) AddChannel(
+	channel domain.Channel,
+) error {
+
+	service.log("Adding channel (%s)", channel.Code)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change channels")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	err = validation.ValidateChannel(channel)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	_, exists, err := service.Repo.GetChannel(channel.Code)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if exists {
+		service.log("Channel already exists")
+
+		return validation.GetChannelExistsError(channel.Code)
+	}
+
+	err = service.Repo.AddChannel(channel)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Added channel")
+
+	return nil
+}
+
+// Only the name can be changed, the code in the body is ignored
+func (service ChannelServiceImpl) UpdateChannel(
+	code string,
+	channel domain.Channel,
+) error {
+
+	service.log("Updating channel (%s)", code)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change channels")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	channel.Code = code
+
+	err = validation.ValidateChannel(channel)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	_, err = service.GetChannel(code)
+
+	if err != nil {
+		return err
+	}
+
+	err = service.Repo.UpdateChannel(code, channel)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Updated channel")
+
+	return nil
+}
+
+/*
+A channel can only be deleted once no product is listed in
+it anymore, published or not.
+*/
+func (service ChannelServiceImpl) DeleteChannel(
+	code string,
+) error {
+
+	service.log("Deleting channel (%s)", code)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change channels")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return err
+	}
+
+	_, err = service.GetChannel(code)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := service.Repo.CountChannelProducts(code)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if count > 0 {
+		service.log("Channel is still in use")
+
+		return fmt.Errorf("Channel (%s) is still used by %v products", code, count)
+	}
+
+	err = service.Repo.DeleteChannel(code)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Deleted channel")
+
+	return nil
+}
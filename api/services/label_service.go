@@ -0,0 +1,227 @@
+package services
+
+import (
+	"api/barcodes"
+	"api/documents"
+	"api/domain"
+	"api/util"
+	"api/validation"
+)
+
+/*
+The label service reads every product through the product
+service instead of the repository. That way the labels
+always show the same data that the api would return for
+the product.
+*/
+type LabelServiceImpl struct {
+	Products domain.ProductService
+	Metadata util.Metadata
+}
+
+var defaultLabelFontSizes = map[string]float64{
+	"title":       10,
+	"sku":         7,
+	"description": 7,
+	"price":       16,
+}
+
+const defaultLabelBarcodeHeight = 12
+
+const labelMargin = 2 * documents.MillimetersToPoints
+
+func (service LabelServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func getLabelProductFields(labelFields []string) []string {
+	fields := []string{"productId"}
+
+	for _, field := range labelFields {
+		if field == "barcode" {
+			fields = append(fields, "barcodes")
+		} else {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// Cuts the text off so that it fits on a single line of the label
+func fitText(text string, fontSize float64, width float64) string {
+	runes := []rune(text)
+
+	if documents.EstimateTextWidth(text, fontSize) <= width {
+		return text
+	}
+
+	for len(runes) > 0 && documents.EstimateTextWidth(string(runes)+"...", fontSize) > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "..."
+}
+
+func drawLabelBarcode(
+	page *documents.PDFPage,
+	symbol barcodes.Symbol,
+	x float64,
+	y float64,
+	width float64,
+	height float64,
+) {
+
+	columns := len(symbol.Modules[0])
+	moduleWidth := width / float64(columns)
+	moduleHeight := height
+
+	if !symbol.Linear {
+		moduleWidth = height / float64(len(symbol.Modules))
+
+		if moduleWidth*float64(columns) > width {
+			moduleWidth = width / float64(columns)
+		}
+
+		moduleHeight = moduleWidth
+	}
+
+	for row, modules := range symbol.Modules {
+		for column, dark := range modules {
+			if dark {
+				page.Rect(
+					x+float64(column)*moduleWidth,
+					y+float64(row)*moduleHeight,
+					moduleWidth,
+					moduleHeight,
+				)
+			}
+		}
+	}
+}
+
+func drawLabel(
+	page *documents.PDFPage,
+	product domain.Product,
+	template domain.LabelTemplate,
+) error {
+
+	width := template.Width*documents.MillimetersToPoints - 2*labelMargin
+	y := labelMargin
+
+	for _, field := range template.Fields {
+		if field == "barcode" {
+			if len(product.Barcodes) == 0 {
+				continue
+			}
+
+			symbol, err := encodeBarcode(product.Barcodes[0], "")
+
+			if err != nil {
+				return err
+			}
+
+			height := template.BarcodeHeight * documents.MillimetersToPoints
+
+			drawLabelBarcode(page, symbol, labelMargin, y, width, height)
+
+			y += height + labelMargin
+			continue
+		}
+
+		fontSize := template.FontSizes[field]
+		font := documents.FontRegular
+		var text string
+
+		switch field {
+		case "title":
+			text = product.Title
+			font = documents.FontBold
+		case "sku":
+			text = product.Sku
+		case "description":
+			if product.Description != nil {
+				text = *product.Description
+			}
+		case "price":
+			text = product.Price
+			font = documents.FontBold
+		}
+
+		y += fontSize
+		page.Text(labelMargin, y, font, fontSize, fitText(text, fontSize, width))
+		y += fontSize * 0.3
+	}
+
+	return nil
+}
+
+func (service LabelServiceImpl) GetLabelsPDF(
+	input domain.LabelInput,
+) ([]byte, error) {
+
+	service.log("Printing labels for %v products", len(input.ProductIDs))
+
+	err := validation.ValidateLabelInput(input)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	template := input.Template
+	fontSizes := map[string]float64{}
+
+	for field, fontSize := range defaultLabelFontSizes {
+		fontSizes[field] = fontSize
+	}
+
+	for field, fontSize := range template.FontSizes {
+		fontSizes[field] = fontSize
+	}
+
+	template.FontSizes = fontSizes
+
+	if template.BarcodeHeight == 0 {
+		template.BarcodeHeight = defaultLabelBarcodeHeight
+	}
+
+	fields := getLabelProductFields(template.Fields)
+	locales := []string{}
+
+	if input.Locale != "" {
+		locales = append(locales, input.Locale)
+	}
+
+	pdf := documents.NewPDF()
+
+	for _, id := range input.ProductIDs {
+		product, err := service.Products.GetProduct(id, fields, locales)
+
+		if err != nil {
+			return nil, err
+		}
+
+		page := pdf.AddPage(
+			template.Width*documents.MillimetersToPoints,
+			template.Height*documents.MillimetersToPoints,
+		)
+
+		err = drawLabel(page, *product, template)
+
+		if err != nil {
+			service.log("Could not draw label for product %v", id)
+
+			return nil, err
+		}
+	}
+
+	service.log("Sending back labels")
+
+	return pdf.Bytes(), nil
+}
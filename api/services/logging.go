@@ -0,0 +1,28 @@
+package services
+
+import (
+	"api/util"
+	"fmt"
+	"log"
+)
+
+/*
+Every service logs with the id of the request it is
+handling so that all the lines of one request can be
+found together.
+*/
+func logForRequest(
+	metadata util.Metadata,
+	format string,
+	values ...interface{},
+) {
+
+	message := fmt.Sprintf(format, values...)
+	log.Printf(
+		"requestId=%v tenant=%s identity=%s data/message=%s",
+		metadata.RequestID,
+		metadata.Tenant,
+		metadata.Identity,
+		message,
+	)
+}
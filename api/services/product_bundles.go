@@ -0,0 +1,81 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"errors"
+	"fmt"
+)
+
+/*
+Every component has to exist and the bundle must not end up
+inside itself, neither directly nor through one of the
+bundles among its components. Pass zero as the id for a
+bundle that does not exist yet, nothing can contain it.
+*/
+func (service ProductServiceImpl) validateBundle(
+	id domain.ProductId,
+	components []domain.BundleComponent,
+) error {
+
+	toVisit := []domain.ProductId{}
+
+	for _, component := range components {
+		if component.ProductID == id {
+			return errors.New("A bundle can't contain itself")
+		}
+
+		exists, err := service.Repo.ProductExists(component.ProductID)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+
+		if !exists {
+			service.log("Can't find component with id %v", component.ProductID)
+
+			return fmt.Errorf("Can't find bundle component %v", component.ProductID)
+		}
+
+		toVisit = append(toVisit, component.ProductID)
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	visited := map[domain.ProductId]struct{}{}
+
+	for len(toVisit) > 0 {
+		nested, err := service.Repo.GetBundleComponents(toVisit)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+
+		for _, productID := range toVisit {
+			visited[productID] = struct{}{}
+		}
+
+		toVisit = []domain.ProductId{}
+
+		for _, bundleComponents := range nested {
+			for _, component := range bundleComponents {
+				if component.ProductID == id {
+					return errors.New("A bundle can't contain itself through one of its components")
+				}
+
+				_, seen := visited[component.ProductID]
+
+				if !seen {
+					visited[component.ProductID] = struct{}{}
+					toVisit = append(toVisit, component.ProductID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
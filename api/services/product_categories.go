@@ -0,0 +1,81 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+)
+
+/*
+Turns the category the client asked for into the ids that
+the repository filters on. Asking for a category that does
+not exist is an error rather than an empty listing.
+*/
+func (service ProductServiceImpl) resolveCategoryFilter(
+	filter domain.ProductFilter,
+) (domain.ProductFilter, error) {
+
+	if filter.Category == nil {
+		return filter, nil
+	}
+
+	categories, err := service.CategoryRepo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return filter, validation.GetGenericDatabaseError()
+	}
+
+	exists := false
+
+	for _, category := range categories {
+		if category.CategoryID == *filter.Category {
+			exists = true
+		}
+	}
+
+	if !exists {
+		service.log("Can't find category %v", *filter.Category)
+
+		return filter, validation.GetCategoryNotFoundError(*filter.Category)
+	}
+
+	if filter.IncludeSubcategories {
+		filter.CategoryIDs = getCategoryWithSubcategories(categories, *filter.Category)
+	} else {
+		filter.CategoryIDs = []domain.CategoryId{*filter.Category}
+	}
+
+	return filter, nil
+}
+
+func (service ProductServiceImpl) validateCategoriesExist(
+	categoryIDs []domain.CategoryId,
+) error {
+
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	categories, err := service.CategoryRepo.GetCategories()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	existing := map[domain.CategoryId]struct{}{}
+
+	for _, category := range categories {
+		existing[category.CategoryID] = struct{}{}
+	}
+
+	for _, categoryID := range categoryIDs {
+		_, exists := existing[categoryID]
+
+		if !exists {
+			return validation.GetCategoryNotFoundError(categoryID)
+		}
+	}
+
+	return nil
+}
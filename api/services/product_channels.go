@@ -0,0 +1,152 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"fmt"
+)
+
+func (service ProductServiceImpl) GetProductChannels(
+	id domain.ProductId,
+) ([]domain.ProductChannel, error) {
+
+	service.log("Requesting channels of product with id %v", id)
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return nil, fmt.Errorf("Can't find product %v", id)
+	}
+
+	channels, err := service.Repo.GetChannels(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return channels, nil
+}
+
+func (service ProductServiceImpl) SetProductChannels(
+	id domain.ProductId,
+	channels []domain.ProductChannel,
+) error {
+
+	service.log("Setting channels of product with id %v", id)
+
+	err := service.authorizeEdit()
+
+	if err != nil {
+		return err
+	}
+
+	err = validation.ValidateProductChannels(channels)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return fmt.Errorf("Can't find product %v", id)
+	}
+
+	current, err := service.Repo.GetChannels(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	err = service.authorizeChannels(current, channels)
+
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		_, exists, err := service.ChannelRepo.GetChannel(channel.Channel)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+
+		if !exists {
+			service.log("Can't find channel (%s)", channel.Channel)
+
+			return fmt.Errorf("Can't find channel (%s)", channel.Channel)
+		}
+	}
+
+	err = service.Repo.SetChannels(id, channels)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Set %v channels", len(channels))
+
+	return nil
+}
+
+/*
+Replaces the title and price of every product with the ones
+of the channel. The product id is needed to find the
+overrides so the caller has to make sure it was read.
+*/
+func (service ProductServiceImpl) applyChannelOverrides(
+	products []domain.Product,
+	channel string,
+	fields []string,
+) error {
+
+	ids := []domain.ProductId{}
+
+	for _, product := range products {
+		ids = append(ids, product.ProductID)
+	}
+
+	overrides, err := service.Repo.GetChannelOverrides(channel, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		override, exists := overrides[products[i].ProductID]
+
+		if !exists {
+			continue
+		}
+
+		if override.Title != nil && hasField(fields, "title") {
+			products[i].Title = *override.Title
+		}
+
+		if override.Price != nil && hasField(fields, "price") {
+			products[i].Price = *override.Price
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"fmt"
+)
+
+// Related products are only sent back with enough to link to them
+var relatedProductFields = []string{"productId", "title", "sku", "price"}
+
+/*
+Reads the relations of the products together with a summary
+of every related product. The related products of all of
+them are read in one go. Errors are passed on untouched for
+the caller to handle.
+*/
+func (service ProductServiceImpl) loadRelations(
+	ids []domain.ProductId,
+	relationType string,
+	locales []string,
+) (map[domain.ProductId][]domain.ProductRelation, error) {
+
+	relations, err := service.Repo.GetRelations(ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	relatedIDs := []domain.ProductId{}
+	seen := map[domain.ProductId]bool{}
+
+	for _, productRelations := range relations {
+		for _, relation := range productRelations {
+			if relationType != "" && relation.Type != relationType {
+				continue
+			}
+
+			if !seen[relation.ProductID] {
+				seen[relation.ProductID] = true
+				relatedIDs = append(relatedIDs, relation.ProductID)
+			}
+		}
+	}
+
+	relatedProducts := map[domain.ProductId]*domain.Product{}
+
+	if len(relatedIDs) > 0 {
+		products, _, err := service.Repo.GetProducts(
+			0,
+			uint64(len(relatedIDs)),
+			domain.ProductFilter{
+				ProductIDs: relatedIDs,
+			},
+			getRepositoryFields(relatedProductFields, locales),
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		toPrepare := make([]*domain.Product, len(products))
+
+		for i := range products {
+			toPrepare[i] = &products[i]
+			relatedProducts[products[i].ProductID] = &products[i]
+		}
+
+		err = service.prepareProducts(toPrepare, relatedProductFields, locales)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := map[domain.ProductId][]domain.ProductRelation{}
+
+	for _, id := range ids {
+		result[id] = []domain.ProductRelation{}
+
+		for _, relation := range relations[id] {
+			if relationType != "" && relation.Type != relationType {
+				continue
+			}
+
+			related, exists := relatedProducts[relation.ProductID]
+
+			if !exists {
+				continue
+			}
+
+			relation.Product = related
+			result[id] = append(result[id], relation)
+		}
+	}
+
+	return result, nil
+}
+
+func (service ProductServiceImpl) GetProductRelations(
+	id domain.ProductId,
+	relationType string,
+	locales []string,
+) ([]domain.ProductRelation, error) {
+
+	service.log("Requesting relations of product with id %v", id)
+
+	if relationType != "" {
+		err := validation.ValidateRelationType(relationType)
+
+		if err != nil {
+			service.log("Validation failed")
+
+			return nil, err
+		}
+	}
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return nil, fmt.Errorf("Can't find product %v", id)
+	}
+
+	relations, err := service.loadRelations([]domain.ProductId{id}, relationType, locales)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return relations[id], nil
+}
+
+func (service ProductServiceImpl) SetProductRelations(
+	id domain.ProductId,
+	relations []domain.ProductRelation,
+) error {
+
+	service.log("Setting relations of product with id %v", id)
+
+	err := service.authorizeEdit()
+
+	if err != nil {
+		return err
+	}
+
+	err = validation.ValidateRelations(id, relations)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return err
+	}
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return fmt.Errorf("Can't find product %v", id)
+	}
+
+	for _, relation := range relations {
+		exists, err := service.Repo.ProductExists(relation.ProductID)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+
+		if !exists {
+			service.log("Can't find related product with id %v", relation.ProductID)
+
+			return fmt.Errorf("Can't find related product %v", relation.ProductID)
+		}
+	}
+
+	err = service.Repo.SetRelations(id, relations)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	service.log("Set %v relations", len(relations))
+
+	return nil
+}
@@ -1,16 +1,29 @@
 package services
 
 import (
+	"api/barcodes"
 	"api/domain"
 	"api/util"
 	"api/validation"
+	"errors"
 	"fmt"
-	"log"
 )
 
+/*
+Used for the price facet when the client does not ask
+for any buckets of its own.
+*/
+var defaultPriceBuckets = []string{"0", "10", "50", "100", "500", "1000"}
+
 type ProductServiceImpl struct {
-	Repo     domain.ProductRepository
-	Metadata util.Metadata
+	Repo          domain.ProductRepository
+	AttributeRepo domain.AttributeRepository
+	CategoryRepo  domain.CategoryRepository
+	BrandRepo     domain.BrandRepository
+	ChannelRepo   domain.ChannelRepository
+	Suggestions   domain.ProductSuggestionIndex
+	Config        util.Config
+	Metadata      util.Metadata
 }
 
 func (service ProductServiceImpl) log(
@@ -18,8 +31,7 @@ func (service ProductServiceImpl) log(
 	values ...interface{},
 ) {
 
-	message := fmt.Sprintf(format, values...)
-	log.Printf("requestId=%v data/message=%s", service.Metadata.RequestID, message)
+	logForRequest(service.Metadata, format, values...)
 }
 
 /*
@@ -34,18 +46,282 @@ func (service ProductServiceImpl) handleDatabaseError(
 	service.log("Database error %s", err.Error())
 }
 
+/*
+The barcode types are not stored anywhere, they are
+detected from the barcodes every time a product is read.
+*/
+func setBarcodeTypes(product *domain.Product) {
+	if len(product.Barcodes) == 0 {
+		return
+	}
+
+	product.BarcodeTypes = map[string]string{}
+
+	for _, barcode := range product.Barcodes {
+		product.BarcodeTypes[barcode] = util.DetectBarcodeType(barcode)
+	}
+}
+
+/*
+Attribute labels are only looked up when the client has
+told us which languages it wants. Attributes that have no
+label in any of those languages are sent back without one.
+*/
+func (service ProductServiceImpl) setAttributeLabels(
+	products []*domain.Product,
+	locales []string,
+) error {
+
+	if len(locales) == 0 {
+		return nil
+	}
+
+	definitions, err := service.AttributeRepo.GetAttributeDefinitions()
+
+	if err != nil {
+		return err
+	}
+
+	definitionMap := map[string]domain.AttributeDefinition{}
+
+	for _, definition := range definitions {
+		definitionMap[definition.Name] = definition
+	}
+
+	for _, product := range products {
+		for i, attribute := range product.Attributes {
+			definition, exists := definitionMap[attribute.Name]
+
+			if !exists {
+				continue
+			}
+
+			label, exists := util.MatchLocale(locales, definition.ValueLabels[attribute.Value])
+
+			if exists {
+				product.Attributes[i].Label = label
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, candidate := range fields {
+		if candidate == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// An empty field list means that every field was asked for
+func hasField(fields []string, field string) bool {
+	return len(fields) == 0 || containsField(fields, field)
+}
+
+/*
+The translations are needed to pick a localised title or
+description even when the client did not ask for them, they
+are stripped again in prepareProducts. Relations are not a
+field the repository knows about, they are looked up by the
+product id afterwards.
+*/
+func getRepositoryFields(fields []string, locales []string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	repositoryFields := []string{}
+
+	for _, field := range fields {
+		if field != "relations" {
+			repositoryFields = append(repositoryFields, field)
+		}
+	}
+
+	if containsField(fields, "relations") && !containsField(repositoryFields, "productId") {
+		repositoryFields = append(repositoryFields, "productId")
+	}
+
+	needsTranslations := containsField(fields, "title") || containsField(fields, "description")
+
+	if len(locales) > 0 && needsTranslations && !containsField(fields, "translations") {
+		repositoryFields = append(repositoryFields, "translations")
+	}
+
+	return repositoryFields
+}
+
+/*
+Every field is looked up on its own so a translation that
+only has a title still gets its description from the next
+locale in line, or from the product itself.
+*/
+func localiseProduct(
+	product *domain.Product,
+	fields []string,
+	locales []string,
+) {
+
+	if len(locales) == 0 || len(product.Translations) == 0 {
+		return
+	}
+
+	titles := map[string]string{}
+	descriptions := map[string]string{}
+	translatedLocales := map[string]string{}
+
+	for locale, translation := range product.Translations {
+		if translation.Title != nil {
+			titles[locale] = *translation.Title
+		}
+
+		if translation.Description != nil {
+			descriptions[locale] = *translation.Description
+		}
+
+		translatedLocales[locale] = locale
+	}
+
+	if hasField(fields, "title") {
+		title, exists := util.MatchLocale(locales, titles)
+
+		if exists {
+			product.Title = title
+		}
+	}
+
+	if hasField(fields, "description") {
+		description, exists := util.MatchLocale(locales, descriptions)
+
+		if exists {
+			product.Description = &description
+		}
+	}
+
+	locale, exists := util.MatchLocale(locales, translatedLocales)
+
+	if exists {
+		product.Locale = locale
+	}
+}
+
+/*
+Everything that is added to products after they have been
+read from the repository and before they are sent back.
+*/
+func (service ProductServiceImpl) prepareProducts(
+	products []*domain.Product,
+	fields []string,
+	locales []string,
+) error {
+
+	ids := []domain.ProductId{}
+
+	for _, product := range products {
+		setBarcodeTypes(product)
+		localiseProduct(product, fields, locales)
+
+		if !hasField(fields, "translations") {
+			product.Translations = nil
+		}
+
+		ids = append(ids, product.ProductID)
+	}
+
+	if containsField(fields, "relations") {
+		relations, err := service.loadRelations(ids, "", locales)
+
+		if err != nil {
+			return err
+		}
+
+		for _, product := range products {
+			product.Relations = relations[product.ProductID]
+		}
+	}
+
+	return service.setAttributeLabels(products, locales)
+}
+
+/*
+Barcodes are only checked against the GS1 formats when
+the deployment has asked for strict barcodes.
+*/
+func (service ProductServiceImpl) validateStrictBarcodes(barcodes []string) error {
+	if !service.Config.StrictBarcodes {
+		return nil
+	}
+
+	return validation.ValidateGS1Barcodes(barcodes)
+}
+
+// A brand id of zero is only used to remove the brand
+func (service ProductServiceImpl) validateBrandExists(brandID *domain.BrandId) error {
+	if brandID == nil || *brandID == 0 {
+		return nil
+	}
+
+	_, exists, err := service.BrandRepo.GetBrand(*brandID)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		return validation.GetBrandNotFoundError(*brandID)
+	}
+
+	return nil
+}
+
+/*
+An update only carries the fields that changed so we read
+the product back to get both the title and the sku for
+the suggestion index. A failure here is only logged since
+the update itself already went through.
+*/
+func (service ProductServiceImpl) refreshSuggestion(id domain.ProductId) {
+	product, exists, err := service.Repo.GetProduct(id, []string{"productId", "title", "sku"})
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return
+	}
+
+	if exists {
+		service.Suggestions.Put(domain.ProductSuggestion{
+			ProductID: product.ProductID,
+			Title:     product.Title,
+			Sku:       product.Sku,
+		})
+	}
+}
+
 func (service ProductServiceImpl) GetProducts(
 	start uint64,
 	num uint64,
-	sku string,
-	barcode string,
+	filter domain.ProductFilter,
 	fields []string,
+	locales []string,
 ) ([]domain.Product, uint32, error) {
 
 	service.log("Requesting multiple products")
 
 	err := validation.ValidateFields(fields)
 
+	if err == nil {
+		err = validation.ValidateStatuses(filter.Statuses)
+	}
+
+	if err == nil && filter.Channel != "" {
+		err = validation.ValidateChannelCode(filter.Channel)
+	}
+
 	if err != nil {
 		service.log("Validation failed")
 
@@ -58,21 +334,115 @@ func (service ProductServiceImpl) GetProducts(
 		num = 10
 	}
 
-	products, count, err := service.Repo.GetProducts(start, num, sku, barcode, fields)
+	filter, err = service.resolveCategoryFilter(filter)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repositoryFields := getRepositoryFields(fields, locales)
+
+	// The overrides of the channel are found by product id
+	if filter.Channel != "" && len(repositoryFields) > 0 && !containsField(repositoryFields, "productId") {
+		repositoryFields = append(repositoryFields, "productId")
+	}
+
+	products, count, err := service.Repo.GetProducts(
+		start,
+		num,
+		filter,
+		repositoryFields,
+	)
 
 	if err != nil {
 		service.handleDatabaseError(err)
 		return nil, 0, validation.GetGenericDatabaseError()
 	}
 
+	toPrepare := make([]*domain.Product, len(products))
+
+	for i := range products {
+		toPrepare[i] = &products[i]
+	}
+
+	err = service.prepareProducts(toPrepare, fields, locales)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, 0, validation.GetGenericDatabaseError()
+	}
+
+	if filter.Channel != "" {
+		err = service.applyChannelOverrides(products, filter.Channel, fields)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return nil, 0, validation.GetGenericDatabaseError()
+		}
+
+		if !hasField(fields, "productId") {
+			for i := range products {
+				products[i].ProductID = 0
+			}
+		}
+	}
+
 	service.log("Sending back products")
 
 	return products, count, nil
 }
 
+func (service ProductServiceImpl) GetProductFacets(
+	filter domain.ProductFilter,
+	priceBuckets []string,
+) (*domain.ProductFacets, error) {
+
+	service.log("Requesting product facets")
+
+	if len(priceBuckets) == 0 {
+		service.log("Default value init for price buckets")
+
+		priceBuckets = defaultPriceBuckets
+	}
+
+	err := validation.ValidatePriceBuckets(priceBuckets)
+
+	if err == nil {
+		err = validation.ValidateStatuses(filter.Statuses)
+	}
+
+	if err == nil && filter.Channel != "" {
+		err = validation.ValidateChannelCode(filter.Channel)
+	}
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	filter, err = service.resolveCategoryFilter(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := service.Repo.GetProductFacets(filter, priceBuckets)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Sending back product facets")
+
+	return facets, nil
+}
+
 func (service ProductServiceImpl) GetProduct(
 	id domain.ProductId,
 	fields []string,
+	locales []string,
 ) (*domain.Product, error) {
 
 	service.log("Requested single product with id %v", id)
@@ -85,7 +455,7 @@ func (service ProductServiceImpl) GetProduct(
 		return nil, err
 	}
 
-	product, exists, err := service.Repo.GetProduct(id, fields)
+	product, exists, err := service.Repo.GetProduct(id, getRepositoryFields(fields, locales))
 
 	if err != nil {
 		service.handleDatabaseError(err)
@@ -97,16 +467,161 @@ func (service ProductServiceImpl) GetProduct(
 		return nil, newErr
 	}
 
+	err = service.prepareProducts([]*domain.Product{product}, fields, locales)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return product, nil
+}
+
+func (service ProductServiceImpl) GetProductByBarcode(
+	barcode string,
+	normalize bool,
+	fields []string,
+	locales []string,
+) (*domain.Product, error) {
+
+	service.log("Requested product with barcode (%s)", barcode)
+
+	err := validation.ValidateBarcode(barcode)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	err = validation.ValidateFields(fields)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	candidates := []string{barcode}
+
+	if normalize {
+		candidates = util.GetBarcodeVariants(barcode)
+	}
+
+	productBarcodes, err := service.Repo.GetBarcodes(candidates)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if len(productBarcodes) == 0 {
+		service.log("Can't find barcode (%s)", barcode)
+
+		return nil, validation.GetBarcodeNotFoundError(barcode)
+	}
+
+	/*
+		The barcode exactly as it was scanned always wins
+		over one of its normalised variants.
+	*/
+	productID := productBarcodes[0].ProductID
+
+	for _, productBarcode := range productBarcodes {
+		if productBarcode.Barcode == barcode {
+			productID = productBarcode.ProductID
+		}
+	}
+
+	product, exists, err := service.Repo.GetProduct(productID, getRepositoryFields(fields, locales))
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	} else if !exists {
+		service.log("Barcode (%s) belongs to missing product %v", barcode, productID)
+
+		return nil, validation.GetBarcodeNotFoundError(barcode)
+	}
+
+	err = service.prepareProducts([]*domain.Product{product}, fields, locales)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
 	return product, nil
 }
 
+func (service ProductServiceImpl) SuggestProducts(
+	prefix string,
+	num uint64,
+) ([]domain.ProductSuggestion, error) {
+
+	service.log("Requesting suggestions for prefix (%s)", prefix)
+
+	err := validation.ValidateSuggestionPrefix(prefix)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	if num == 0 {
+		service.log("Default value init for num")
+
+		num = 10
+	}
+
+	if num > 50 {
+		num = 50
+	}
+
+	return service.Suggestions.Find(prefix, int(num)), nil
+}
+
 func (service ProductServiceImpl) AddProduct(
 	product domain.ProductAddInput,
 ) (domain.ProductId, error) {
 
 	service.log("Adding product")
 
-	err := validation.ValidateNewProduct(product)
+	err := service.authorizeEdit()
+
+	if err != nil {
+		return 0, err
+	}
+
+	definitions, err := service.AttributeRepo.GetAttributeDefinitions()
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return 0, validation.GetGenericDatabaseError()
+	}
+
+	err = validation.ValidateNewProduct(product, definitions)
+
+	if err == nil {
+		err = service.validateStrictBarcodes(product.Barcodes)
+	}
+
+	if err == nil && product.ParentID != nil {
+		err = service.validateVariant(0, *product.ParentID, product.Attributes)
+	}
+
+	if err == nil {
+		err = service.validateCategoriesExist(product.CategoryIDs)
+	}
+
+	if err == nil {
+		err = service.validateBrandExists(product.BrandID)
+	}
+
+	if err == nil {
+		err = service.validateBundle(0, product.Components)
+	}
 
 	if err != nil {
 		service.log("Failed validation")
@@ -114,6 +629,10 @@ func (service ProductServiceImpl) AddProduct(
 		return 0, err
 	}
 
+	if product.BrandID != nil && *product.BrandID == 0 {
+		product.BrandID = nil
+	}
+
 	productSku, err := service.Repo.GetSku(product.Sku)
 
 	if err != nil {
@@ -142,13 +661,42 @@ func (service ProductServiceImpl) AddProduct(
 		}
 	}
 
-	id, err := service.Repo.AddProduct(product)
+	barcodePrefix := ""
+
+	if product.GenerateBarcode {
+		err := validation.ValidateBarcodePrefix(
+			service.Config.BarcodePrefix,
+			service.Config.GS1CompanyPrefix,
+		)
+
+		if err != nil {
+			service.log("Can't generate barcode")
+
+			return 0, err
+		}
+
+		barcodePrefix = service.Config.BarcodePrefix
+	}
+
+	id, err := service.Repo.AddProduct(product, barcodePrefix)
+
+	if err == domain.ErrBarcodeRangeExhausted {
+		service.log("Barcode prefix (%s) is exhausted", barcodePrefix)
+
+		return 0, err
+	}
 
 	if err != nil {
 		service.handleDatabaseError(err)
 		return 0, validation.GetGenericDatabaseError()
 	}
 
+	service.Suggestions.Put(domain.ProductSuggestion{
+		ProductID: id,
+		Title:     product.Title,
+		Sku:       product.Sku,
+	})
+
 	service.log("Added product")
 
 	return id, nil
@@ -161,6 +709,12 @@ func (service ProductServiceImpl) UpdateProduct(
 
 	service.log("Updating product with id (%v)", id)
 
+	err := service.authorizeUpdate(product)
+
+	if err != nil {
+		return err
+	}
+
 	exists, err := service.Repo.ProductExists(id)
 
 	if err != nil {
@@ -174,7 +728,42 @@ func (service ProductServiceImpl) UpdateProduct(
 		return fmt.Errorf("Can't find product %v", id)
 	}
 
-	err = validation.ValidateProductUpdate(product)
+	var definitions []domain.AttributeDefinition
+
+	if product.Attributes != nil {
+		definitions, err = service.AttributeRepo.GetAttributeDefinitions()
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+	}
+
+	err = validation.ValidateProductUpdate(product, definitions)
+
+	if err == nil {
+		err = service.validateStrictBarcodes(product.Barcodes)
+	}
+
+	if err == nil {
+		err = service.validateVariantUpdate(id, product)
+	}
+
+	if err == nil {
+		err = service.validateCategoriesExist(product.CategoryIDs)
+	}
+
+	if err == nil {
+		err = service.validateBrandExists(product.BrandID)
+	}
+
+	if err == nil {
+		err = service.validateBundle(id, product.Components)
+	}
+
+	if err == nil {
+		product, err = service.applyStatusChange(id, product)
+	}
 
 	if err != nil {
 		return err
@@ -218,6 +807,10 @@ func (service ProductServiceImpl) UpdateProduct(
 		return validation.GetGenericDatabaseError()
 	}
 
+	if product.Title != nil || product.Sku != nil {
+		service.refreshSuggestion(id)
+	}
+
 	service.log("Updated product")
 
 	return nil
@@ -229,6 +822,12 @@ func (service ProductServiceImpl) DeleteProduct(
 
 	service.log("Deleting product with id: (%v)", id)
 
+	err := service.authorizeDelete()
+
+	if err != nil {
+		return err
+	}
+
 	exists, err := service.Repo.ProductExists(id)
 
 	if err != nil {
@@ -242,6 +841,32 @@ func (service ProductServiceImpl) DeleteProduct(
 		return fmt.Errorf("Product with productId (%v) does not exist", id)
 	}
 
+	variantCount, err := service.Repo.CountVariants(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if variantCount > 0 {
+		service.log("Product still has variants")
+
+		return fmt.Errorf("Product with productId (%v) still has %v variants", id, variantCount)
+	}
+
+	bundleCount, err := service.Repo.CountBundlesContaining(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if bundleCount > 0 {
+		service.log("Product is still part of a bundle")
+
+		return fmt.Errorf("Product with productId (%v) is still a component of %v bundles", id, bundleCount)
+	}
+
 	err = service.Repo.DeleteProduct(id)
 
 	if err != nil {
@@ -249,7 +874,184 @@ func (service ProductServiceImpl) DeleteProduct(
 		return validation.GetGenericDatabaseError()
 	}
 
+	service.Suggestions.Remove(id)
+
 	service.log("Deleted product")
 
 	return nil
 }
+
+func (service ProductServiceImpl) GenerateBarcode(
+	id domain.ProductId,
+) (*domain.ProductBarcode, error) {
+
+	service.log("Generating barcode for product with id (%v)", id)
+
+	err := service.authorizeEdit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validation.ValidateBarcodePrefix(
+		service.Config.BarcodePrefix,
+		service.Config.GS1CompanyPrefix,
+	)
+
+	if err != nil {
+		service.log("Can't generate barcode")
+
+		return nil, err
+	}
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Product does not exist")
+
+		return nil, fmt.Errorf("Product with productId (%v) does not exist", id)
+	}
+
+	status, err := service.getStatus(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status == domain.ProductStatusArchived {
+		service.log("Product is archived")
+
+		return nil, errors.New("An archived product can't have barcodes")
+	}
+
+	barcode, err := service.Repo.GenerateBarcode(id, service.Config.BarcodePrefix)
+
+	if err == domain.ErrBarcodeRangeExhausted {
+		service.log("Barcode prefix (%s) is exhausted", service.Config.BarcodePrefix)
+
+		return nil, err
+	}
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Generated barcode (%s)", barcode)
+
+	return &domain.ProductBarcode{
+		ProductID: id,
+		Barcode:   barcode,
+	}, nil
+}
+
+func encodeBarcode(
+	barcode string,
+	symbology string,
+) (barcodes.Symbol, error) {
+
+	if symbology == "" {
+		switch util.DetectBarcodeType(barcode) {
+		case util.BarcodeTypeEAN13:
+			symbology = "ean13"
+		case util.BarcodeTypeUPCA:
+			symbology = "upca"
+		default:
+			symbology = "code128"
+		}
+	}
+
+	switch symbology {
+	case "ean13":
+		return barcodes.EncodeEAN13(barcode)
+	case "upca":
+		return barcodes.EncodeUPCA(barcode)
+	case "qr":
+		return barcodes.EncodeQR(barcode)
+	default:
+		return barcodes.EncodeCode128(barcode)
+	}
+}
+
+func (service ProductServiceImpl) GetBarcodeImage(
+	id domain.ProductId,
+	barcode string,
+	options domain.BarcodeImageOptions,
+) ([]byte, error) {
+
+	service.log("Rendering barcode (%s) of product with id (%v)", barcode, id)
+
+	err := validation.ValidateBarcode(barcode)
+
+	if err == nil {
+		err = validation.ValidateBarcodeImageOptions(options)
+	}
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	productBarcodes, err := service.Repo.GetBarcodes([]string{barcode})
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if len(productBarcodes) == 0 || productBarcodes[0].ProductID != id {
+		service.log("Product does not have barcode (%s)", barcode)
+
+		return nil, validation.GetBarcodeNotFoundError(barcode)
+	}
+
+	symbol, err := encodeBarcode(barcode, options.Symbology)
+
+	if err != nil {
+		service.log("Could not encode barcode")
+
+		return nil, err
+	}
+
+	renderOptions := barcodes.RenderOptions{
+		ModuleSize: options.ModuleSize,
+		BarHeight:  options.BarHeight,
+		QuietZone:  10,
+	}
+
+	if !symbol.Linear {
+		renderOptions.QuietZone = 4
+	}
+
+	if options.QuietZone != nil {
+		renderOptions.QuietZone = *options.QuietZone
+	}
+
+	if renderOptions.ModuleSize == 0 {
+		renderOptions.ModuleSize = 2
+	}
+
+	if renderOptions.BarHeight == 0 {
+		renderOptions.BarHeight = 60
+	}
+
+	if options.Format == "svg" {
+		return barcodes.RenderSVG(symbol, renderOptions), nil
+	}
+
+	image, err := barcodes.RenderPNG(symbol, renderOptions)
+
+	if err != nil {
+		service.log("Could not render barcode with error %s", err.Error())
+
+		return nil, errors.New("Could not render barcode")
+	}
+
+	return image, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"errors"
+)
+
+var statusFields = []string{"status"}
+
+func (service ProductServiceImpl) getStatus(id domain.ProductId) (string, error) {
+
+	current, _, err := service.Repo.GetProduct(id, statusFields)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return "", validation.GetGenericDatabaseError()
+	}
+
+	return current.Status, nil
+}
+
+/*
+Checks the status change against the current status of the
+product. Archiving a product releases its barcodes so they
+are cleared in the returned update, and an archived product
+can't be given any new ones until it's restored.
+*/
+func (service ProductServiceImpl) applyStatusChange(
+	id domain.ProductId,
+	product domain.ProductUpdateInput,
+) (domain.ProductUpdateInput, error) {
+
+	if product.Status == nil && len(product.Barcodes) == 0 {
+		return product, nil
+	}
+
+	current, err := service.getStatus(id)
+
+	if err != nil {
+		return product, err
+	}
+
+	status := current
+
+	if product.Status != nil {
+		err := validation.ValidateStatusTransition(current, *product.Status)
+
+		if err != nil {
+			return product, err
+		}
+
+		status = *product.Status
+	}
+
+	if status != domain.ProductStatusArchived {
+		return product, nil
+	}
+
+	if len(product.Barcodes) > 0 {
+		return product, errors.New("An archived product can't have barcodes")
+	}
+
+	if current != domain.ProductStatusArchived {
+		service.log("Releasing barcodes of product with id (%v)", id)
+
+		product.Barcodes = []string{}
+	}
+
+	return product, nil
+}
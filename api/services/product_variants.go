@@ -0,0 +1,119 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"errors"
+	"fmt"
+)
+
+var variantParentFields = []string{"productId", "parentId", "variantAxes"}
+
+/*
+Checks the attributes of a new or updated variant against
+the axes of its parent and the variants it already has.
+Pass zero as the id for a product that does not exist yet.
+*/
+func (service ProductServiceImpl) validateVariant(
+	id domain.ProductId,
+	parentID domain.ProductId,
+	attributes []domain.ProductAttribute,
+) error {
+
+	parent, exists, err := service.Repo.GetProduct(parentID, variantParentFields)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find parent product with id %v", parentID)
+
+		return fmt.Errorf("Can't find parent product %v", parentID)
+	}
+
+	siblings, err := service.Repo.GetVariantAttributes(parentID)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	return validation.ValidateVariant(id, *parent, attributes, siblings)
+}
+
+/*
+Variants are checked again whenever their attributes are
+replaced, and the axes of a parent are locked as soon as
+it has a variant since those would no longer be valid.
+*/
+func (service ProductServiceImpl) validateVariantUpdate(
+	id domain.ProductId,
+	product domain.ProductUpdateInput,
+) error {
+
+	if product.Attributes == nil && product.VariantAxes == nil {
+		return nil
+	}
+
+	current, _, err := service.Repo.GetProduct(id, variantParentFields)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if current.ParentID != nil && product.VariantAxes != nil {
+		return errors.New("A variant can't have variant axes of its own")
+	}
+
+	if current.ParentID != nil && product.Attributes != nil {
+		return service.validateVariant(id, *current.ParentID, product.Attributes)
+	}
+
+	if product.VariantAxes != nil {
+		count, err := service.Repo.CountVariants(id)
+
+		if err != nil {
+			service.handleDatabaseError(err)
+			return validation.GetGenericDatabaseError()
+		}
+
+		if count > 0 {
+			return fmt.Errorf("Can't change the variant axes of product %v while it has variants", id)
+		}
+	}
+
+	return nil
+}
+
+func (service ProductServiceImpl) GetVariants(
+	id domain.ProductId,
+	start uint64,
+	num uint64,
+	fields []string,
+	locales []string,
+) ([]domain.Product, uint32, error) {
+
+	service.log("Requesting variants of product with id %v", id)
+
+	exists, err := service.Repo.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, 0, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return nil, 0, fmt.Errorf("Can't find product %v", id)
+	}
+
+	filter := domain.ProductFilter{
+		ParentID: &id,
+	}
+
+	return service.GetProducts(start, num, filter, fields, locales)
+}
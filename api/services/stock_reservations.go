@@ -0,0 +1,163 @@
+package services
+
+import (
+	"api/domain"
+	"api/validation"
+	"time"
+)
+
+// Used when the client does not say how long to hold the stock
+const defaultReservationTTL = 15 * time.Minute
+
+func (service StockServiceImpl) ReserveStock(
+	id domain.ProductId,
+	reservation domain.StockReservationInput,
+) (*domain.StockReservation, error) {
+
+	service.log("Reserving stock of product with id %v at (%s)", id, reservation.Location)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change stock")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return nil, err
+	}
+
+	err = validation.ValidateStockReservation(reservation)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	err = service.validateProductExists(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := defaultReservationTTL
+
+	if reservation.TTLSeconds > 0 {
+		ttl = time.Duration(reservation.TTLSeconds) * time.Second
+	}
+
+	created, err := service.Repo.ReserveStock(id, reservation, time.Now().Add(ttl))
+
+	if err == domain.ErrInsufficientStock {
+		service.log("Not enough stock to reserve %v", reservation.Quantity)
+
+		return nil, err
+	}
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Created reservation %v", created.ReservationID)
+
+	return created, nil
+}
+
+func (service StockServiceImpl) GetReservation(
+	id uint64,
+) (*domain.StockReservation, error) {
+
+	service.log("Requesting reservation %v", id)
+
+	reservation, exists, err := service.Repo.GetReservation(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find reservation %v", id)
+
+		return nil, validation.GetReservationNotFoundError(id)
+	}
+
+	return reservation, nil
+}
+
+/*
+Confirming and releasing share everything except what the
+repository does with the reservation.
+*/
+func (service StockServiceImpl) finishReservation(
+	id uint64,
+	finish func(id uint64) (*domain.StockReservation, error),
+) (*domain.StockReservation, error) {
+	err := authorizeEditor(service.Metadata, "Only editors can change stock")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return nil, err
+	}
+
+	_, err = service.GetReservation(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := finish(id)
+
+	if err == domain.ErrReservationNotActive {
+		service.log("Reservation %v is no longer active", id)
+
+		return nil, err
+	}
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Reservation %v is now %s", id, reservation.Status)
+
+	return reservation, nil
+}
+
+func (service StockServiceImpl) ConfirmReservation(
+	id uint64,
+) (*domain.StockReservation, error) {
+
+	service.log("Confirming reservation %v", id)
+
+	return service.finishReservation(id, service.Repo.ConfirmReservation)
+}
+
+func (service StockServiceImpl) ReleaseReservation(
+	id uint64,
+) (*domain.StockReservation, error) {
+
+	service.log("Releasing reservation %v", id)
+
+	return service.finishReservation(id, service.Repo.ReleaseReservation)
+}
+
+/*
+Expired reservations already stop holding stock the moment
+they expire, the sweeper only makes their status say so.
+*/
+func (service StockServiceImpl) ExpireReservations() error {
+
+	count, err := service.Repo.ExpireReservations(time.Now())
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if count > 0 {
+		service.log("Expired %v reservations", count)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"fmt"
+)
+
+type StockServiceImpl struct {
+	Repo     domain.StockRepository
+	Products domain.ProductRepository
+	Metadata util.Metadata
+}
+
+func (service StockServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service StockServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+func (service StockServiceImpl) validateProductExists(id domain.ProductId) error {
+	exists, err := service.Products.ProductExists(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find product with id %v", id)
+
+		return fmt.Errorf("Can't find product %v", id)
+	}
+
+	return nil
+}
+
+func (service StockServiceImpl) GetStock(
+	id domain.ProductId,
+) ([]domain.StockLevel, error) {
+
+	service.log("Requesting stock of product with id %v", id)
+
+	err := service.validateProductExists(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := service.Repo.GetStockLevels(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return levels, nil
+}
+
+func (service StockServiceImpl) AdjustStock(
+	id domain.ProductId,
+	adjustment domain.StockAdjustment,
+) ([]domain.StockLevel, error) {
+
+	service.log("Adjusting stock of product with id %v at (%s)", id, adjustment.Location)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change stock")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return nil, err
+	}
+
+	err = validation.ValidateStockAdjustment(adjustment)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	err = service.validateProductExists(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	movements := []domain.StockMovement{
+		{
+			Location:  adjustment.Location,
+			Delta:     adjustment.Delta,
+			Reason:    adjustment.Reason,
+			Reference: adjustment.Reference,
+		},
+	}
+
+	if adjustment.Reason == domain.StockReasonTransfer {
+		movements[0].Delta = -adjustment.Delta
+
+		movements = append(movements, domain.StockMovement{
+			Location:  adjustment.ToLocation,
+			Delta:     adjustment.Delta,
+			Reason:    adjustment.Reason,
+			Reference: adjustment.Reference,
+		})
+	}
+
+	levels, err := service.Repo.AddMovements(id, movements)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Added %v stock movements", len(movements))
+
+	return levels, nil
+}
+
+func (service StockServiceImpl) GetStockMovements(
+	id domain.ProductId,
+	location string,
+	start uint64,
+	num uint64,
+) ([]domain.StockMovement, uint32, error) {
+
+	service.log("Requesting stock movements of product with id %v", id)
+
+	if num == 0 {
+		service.log("Default value init for num")
+
+		num = 50
+	}
+
+	if num > 500 {
+		num = 500
+	}
+
+	err := service.validateProductExists(id)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	movements, count, err := service.Repo.GetMovements(id, location, start, num)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, 0, validation.GetGenericDatabaseError()
+	}
+
+	return movements, count, nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"fmt"
+)
+
+type StocktakeServiceImpl struct {
+	Repo     domain.StocktakeRepository
+	Products domain.ProductRepository
+	Metadata util.Metadata
+}
+
+func (service StocktakeServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service StocktakeServiceImpl) handleDatabaseError(
+	err error,
+) {
+	service.log("Database error %s", err.Error())
+}
+
+func (service StocktakeServiceImpl) OpenStocktake(
+	location string,
+) (uint64, error) {
+
+	service.log("Opening stocktake at (%s)", location)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change stocktakes")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return 0, err
+	}
+
+	err = validation.ValidateStocktakeLocation(location)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return 0, err
+	}
+
+	id, err := service.Repo.AddStocktake(location)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return 0, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Opened stocktake %v", id)
+
+	return id, nil
+}
+
+func (service StocktakeServiceImpl) GetStocktake(
+	id uint64,
+) (*domain.Stocktake, error) {
+
+	service.log("Requesting stocktake %v", id)
+
+	stocktake, exists, err := service.Repo.GetStocktake(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	if !exists {
+		service.log("Can't find stocktake %v", id)
+
+		return nil, validation.GetStocktakeNotFoundError(id)
+	}
+
+	return stocktake, nil
+}
+
+/*
+Every barcode has to belong to a product before anything is
+counted so a typo doesn't leave half of a batch behind.
+*/
+func (service StocktakeServiceImpl) AddStocktakeScans(
+	id uint64,
+	scans []domain.StocktakeScan,
+) (*domain.Stocktake, error) {
+
+	service.log("Adding %v scans to stocktake %v", len(scans), id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change stocktakes")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return nil, err
+	}
+
+	err = validation.ValidateStocktakeScans(scans)
+
+	if err != nil {
+		service.log("Validation failed")
+
+		return nil, err
+	}
+
+	stocktake, err := service.GetStocktake(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stocktake.Status != domain.StocktakeStatusOpen {
+		service.log("Stocktake %v is already %s", id, stocktake.Status)
+
+		return nil, domain.ErrStocktakeNotOpen
+	}
+
+	barcodes := []string{}
+
+	for _, scan := range scans {
+		barcodes = append(barcodes, scan.Barcode)
+	}
+
+	productBarcodes, err := service.Products.GetBarcodes(barcodes)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	barcodeProducts := map[string]domain.ProductId{}
+
+	for _, productBarcode := range productBarcodes {
+		barcodeProducts[productBarcode.Barcode] = productBarcode.ProductID
+	}
+
+	counts := []domain.StocktakeCount{}
+	countIndexes := map[domain.ProductId]int{}
+
+	for _, scan := range scans {
+		productID, found := barcodeProducts[scan.Barcode]
+
+		if !found {
+			service.log("Unknown barcode (%s)", scan.Barcode)
+
+			return nil, fmt.Errorf("Barcode (%s) doesn't belong to any product", scan.Barcode)
+		}
+
+		quantity := scan.Quantity
+
+		if quantity == 0 {
+			quantity = 1
+		}
+
+		index, exists := countIndexes[productID]
+
+		if exists {
+			counts[index].Counted += quantity
+			continue
+		}
+
+		countIndexes[productID] = len(counts)
+		counts = append(counts, domain.StocktakeCount{
+			ProductID: productID,
+			Counted:   quantity,
+		})
+	}
+
+	err = service.Repo.AddCounts(id, counts)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return service.GetStocktake(id)
+}
+
+func (service StocktakeServiceImpl) GetStocktakeDiscrepancies(
+	id uint64,
+) ([]domain.StocktakeDiscrepancy, error) {
+
+	service.log("Requesting discrepancies of stocktake %v", id)
+
+	_, err := service.GetStocktake(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies, err := service.Repo.GetDiscrepancies(id)
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	return discrepancies, nil
+}
+
+func (service StocktakeServiceImpl) CommitStocktake(
+	id uint64,
+) ([]domain.StocktakeDiscrepancy, error) {
+
+	service.log("Committing stocktake %v", id)
+
+	err := authorizeEditor(service.Metadata, "Only editors can change stocktakes")
+
+	if err != nil {
+		service.log("Caller (%s) is not an editor", service.Metadata.Identity)
+
+		return nil, err
+	}
+
+	_, err = service.GetStocktake(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted, err := service.Repo.CommitStocktake(id)
+
+	if err == domain.ErrStocktakeNotOpen {
+		service.log("Stocktake %v is already committed", id)
+
+		return nil, err
+	}
+
+	if err != nil {
+		service.handleDatabaseError(err)
+		return nil, validation.GetGenericDatabaseError()
+	}
+
+	service.log("Committed stocktake %v with %v adjustments", id, len(adjusted))
+
+	return adjusted, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"api/domain"
+	"api/util"
+	"api/validation"
+	"time"
+)
+
+type TokenServiceImpl struct {
+	Keys     *util.KeySet
+	Config   util.Config
+	Metadata util.Metadata
+}
+
+func (service TokenServiceImpl) log(
+	format string,
+	values ...interface{},
+) {
+
+	logForRequest(service.Metadata, format, values...)
+}
+
+func (service TokenServiceImpl) VerifyToken(
+	token string,
+) (*domain.TokenClaims, error) {
+
+	if service.Keys == nil {
+		service.log("Bearer token without a configured key set")
+
+		return nil, validation.GetUnauthorizedError("Bearer tokens are not accepted")
+	}
+
+	claims, err := util.ParseToken(token, service.Keys)
+
+	if err != nil {
+		service.log("Invalid token %s", err.Error())
+
+		return nil, validation.GetUnauthorizedError("Invalid token")
+	}
+
+	err = validation.ValidateTokenClaims(
+		*claims,
+		service.Config.JWTIssuer,
+		service.Config.JWTAudience,
+		time.Now().Unix(),
+	)
+
+	if err != nil {
+		service.log("Token of (%s) rejected: %s", claims.Subject, err.Error())
+
+		return nil, err
+	}
+
+	return &domain.TokenClaims{
+		Subject: claims.Subject,
+		Tenant:  claims.Tenant,
+		Roles:   claims.Roles,
+	}, nil
+}
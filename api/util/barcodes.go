@@ -0,0 +1,151 @@
+package util
+
+import (
+	"strings"
+)
+
+func isDigits(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+A UPC-A barcode is an EAN-13 barcode with a leading zero
+that has been dropped. Scanners are not consistent about
+which one of the two they report so we return both forms
+of the barcode when it is one of them.
+*/
+func GetBarcodeVariants(barcode string) []string {
+	variants := []string{barcode}
+
+	if !isDigits(barcode) {
+		return variants
+	}
+
+	if len(barcode) == 12 {
+		variants = append(variants, "0"+barcode)
+	}
+
+	if len(barcode) == 13 && strings.HasPrefix(barcode, "0") {
+		variants = append(variants, barcode[1:])
+	}
+
+	return variants
+}
+
+const (
+	BarcodeTypeEAN8   = "EAN-8"
+	BarcodeTypeEAN13  = "EAN-13"
+	BarcodeTypeUPCA   = "UPC-A"
+	BarcodeTypeUPCE   = "UPC-E"
+	BarcodeTypeGTIN14 = "GTIN-14"
+	BarcodeTypeITF14  = "ITF-14"
+	BarcodeTypeOther  = "OTHER"
+)
+
+/*
+Calculates the GS1 check digit for a barcode that does
+not have its check digit yet. Starting from the right
+every other digit is weighted by three.
+*/
+func GetGS1CheckDigit(digits string) byte {
+	sum := 0
+	weight := 3
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+
+		if weight == 3 {
+			weight = 1
+		} else {
+			weight = 3
+		}
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func hasValidGS1CheckDigit(barcode string) bool {
+	last := len(barcode) - 1
+
+	return barcode[last] == GetGS1CheckDigit(barcode[:last])
+}
+
+/*
+UPC-E is a zero suppressed UPC-A barcode. Its check digit
+is calculated on the expanded UPC-A barcode so we have to
+expand it before we can verify it.
+*/
+func ExpandUPCE(barcode string) string {
+	numberSystem := barcode[0:1]
+	data := barcode[1:7]
+	check := barcode[7:8]
+
+	var expanded string
+
+	switch data[5] {
+	case '0', '1', '2':
+		expanded = data[0:2] + data[5:6] + "0000" + data[2:5]
+	case '3':
+		expanded = data[0:3] + "00000" + data[3:5]
+	case '4':
+		expanded = data[0:4] + "00000" + data[4:5]
+	default:
+		expanded = data[0:5] + "0000" + data[5:6]
+	}
+
+	return numberSystem + expanded + check
+}
+
+/*
+Detects which GS1 format a barcode is in by looking at its
+length and check digit. An 8 digit barcode is treated as
+EAN-8 first and as UPC-E only if that check fails. A
+GTIN-14 with a packaging indicator between 1 and 8 is what
+gets printed as an ITF-14 on cartons.
+
+Anything that is not a valid GS1 barcode is OTHER.
+*/
+func DetectBarcodeType(barcode string) string {
+	if !isDigits(barcode) {
+		return BarcodeTypeOther
+	}
+
+	switch len(barcode) {
+	case 8:
+		if hasValidGS1CheckDigit(barcode) {
+			return BarcodeTypeEAN8
+		}
+
+		if (barcode[0] == '0' || barcode[0] == '1') && hasValidGS1CheckDigit(ExpandUPCE(barcode)) {
+			return BarcodeTypeUPCE
+		}
+	case 12:
+		if hasValidGS1CheckDigit(barcode) {
+			return BarcodeTypeUPCA
+		}
+	case 13:
+		if hasValidGS1CheckDigit(barcode) {
+			return BarcodeTypeEAN13
+		}
+	case 14:
+		if hasValidGS1CheckDigit(barcode) {
+			if barcode[0] >= '1' && barcode[0] <= '8' {
+				return BarcodeTypeITF14
+			}
+
+			return BarcodeTypeGTIN14
+		}
+	}
+
+	return BarcodeTypeOther
+}
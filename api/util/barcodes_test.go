@@ -0,0 +1,81 @@
+package util
+
+import "testing"
+
+func TestGetGS1CheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"400638133393", '1'},
+		{"03600029145", '2'},
+		{"9638507", '4'},
+		{"04210000526", '4'},
+		{"1234567", '0'},
+		{"1001234567890", '2'},
+	}
+
+	for _, test := range tests {
+		got := GetGS1CheckDigit(test.digits)
+
+		if got != test.want {
+			t.Errorf("GetGS1CheckDigit(%s) = %c, want %c", test.digits, got, test.want)
+		}
+	}
+}
+
+func TestExpandUPCE(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    string
+	}{
+		{"04252614", "042100005264"},
+		{"01234505", "012000003455"},
+		{"01234535", "012300000455"},
+		{"01234545", "012340000055"},
+		{"01234575", "012345000075"},
+	}
+
+	for _, test := range tests {
+		got := ExpandUPCE(test.barcode)
+
+		if got != test.want {
+			t.Errorf("ExpandUPCE(%s) = %s, want %s", test.barcode, got, test.want)
+		}
+	}
+}
+
+func TestDetectBarcodeType(t *testing.T) {
+	tests := []struct {
+		barcode string
+		want    string
+	}{
+		{"96385074", BarcodeTypeEAN8},
+		{"04252614", BarcodeTypeUPCE},
+		{"036000291452", BarcodeTypeUPCA},
+		{"4006381333931", BarcodeTypeEAN13},
+		{"10012345678902", BarcodeTypeITF14},
+		{"04006381333931", BarcodeTypeGTIN14},
+
+		// Wrong check digits
+		{"96385075", BarcodeTypeOther},
+		{"036000291453", BarcodeTypeOther},
+		{"4006381333932", BarcodeTypeOther},
+		{"10012345678903", BarcodeTypeOther},
+
+		// UPC-E only has number systems 0 and 1
+		{"24252618", BarcodeTypeOther},
+
+		{"", BarcodeTypeOther},
+		{"ABC-123", BarcodeTypeOther},
+		{"12345", BarcodeTypeOther},
+	}
+
+	for _, test := range tests {
+		got := DetectBarcodeType(test.barcode)
+
+		if got != test.want {
+			t.Errorf("DetectBarcodeType(%s) = %s, want %s", test.barcode, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"os"
+	"time"
+)
+
+/*
+The config holds everything that can differ between two
+deployments of the api. It is read from environment
+variables once on startup.
+*/
+type Config struct {
+	StrictBarcodes bool
+
+	/*
+		Either an in-store prefix between 20 and 29 or one
+		that starts with the GS1 company prefix assigned to
+		the retailer. Barcodes can only be generated when
+		this is set.
+	*/
+	BarcodePrefix    string
+	GS1CompanyPrefix string
+
+	// How often expired stock reservations are swept up
+	ReservationSweepInterval time.Duration
+
+	/*
+		Always accepted as an api key, for every tenant, so that
+		the first keys of a tenant can be created.
+	*/
+	AdminApiKey string
+
+	/*
+		Bearer tokens are only accepted when a key set is
+		configured, either as a local file or a url. The
+		issuer and audience of every token have to match.
+	*/
+	JWKSFile    string
+	JWKSURL     string
+	JWTIssuer   string
+	JWTAudience string
+}
+
+func LoadConfig() Config {
+	sweepInterval, err := time.ParseDuration(os.Getenv("RESERVATION_SWEEP_INTERVAL"))
+
+	if err != nil || sweepInterval <= 0 {
+		sweepInterval = time.Minute
+	}
+
+	return Config{
+		StrictBarcodes:           os.Getenv("STRICT_BARCODES") == "true",
+		BarcodePrefix:            os.Getenv("BARCODE_PREFIX"),
+		GS1CompanyPrefix:         os.Getenv("GS1_COMPANY_PREFIX"),
+		ReservationSweepInterval: sweepInterval,
+		AdminApiKey:              os.Getenv("ADMIN_API_KEY"),
+		JWKSFile:                 os.Getenv("JWKS_FILE"),
+		JWKSURL:                  os.Getenv("JWKS_URL"),
+		JWTIssuer:                os.Getenv("JWT_ISSUER"),
+		JWTAudience:              os.Getenv("JWT_AUDIENCE"),
+	}
+}
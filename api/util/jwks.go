@@ -0,0 +1,242 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// How often a key set read from a url may be fetched again
+const keySetRefreshInterval = time.Minute
+
+/*
+The key set holds the public keys that tokens are signed
+with, by key id. A key set read from a url is fetched again
+when a token refers to a key it doesn't know, since that
+usually means that the keys were rotated.
+*/
+type KeySet struct {
+	mutex     sync.Mutex
+	file      string
+	url       string
+	keys      map[string]crypto.PublicKey
+	refreshed time.Time
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func parseJSONWebKey(key jsonWebKey) (crypto.PublicKey, error) {
+
+	switch key.Kty {
+	case "RSA":
+		n, err := decodeBigInt(key.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(key.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		if n.BitLen() < 2048 {
+			return nil, errors.New("RSA keys have to be at least 2048 bits")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if key.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve (%s)", key.Crv)
+		}
+
+		x, err := decodeBigInt(key.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(key.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type (%s)", key.Kty)
+}
+
+/*
+Keys that can't be used for verifying signatures are
+skipped rather than failing the whole set.
+*/
+func parseKeySet(data []byte) (map[string]crypto.PublicKey, error) {
+	set := jsonWebKeySet{}
+
+	err := json.Unmarshal(data, &set)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := parseJSONWebKey(key)
+
+		if err != nil {
+			continue
+		}
+
+		keys[key.Kid] = publicKey
+	}
+
+	return keys, nil
+}
+
+func (set *KeySet) load() error {
+	var data []byte
+	var err error
+
+	if set.file != "" {
+		data, err = ioutil.ReadFile(set.file)
+	} else {
+		client := http.Client{Timeout: 10 * time.Second}
+
+		var response *http.Response
+		response, err = client.Get(set.url)
+
+		if err != nil {
+			return err
+		}
+
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("fetching key set failed with status %v", response.StatusCode)
+		}
+
+		data, err = ioutil.ReadAll(response.Body)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseKeySet(data)
+
+	if err != nil {
+		return err
+	}
+
+	set.keys = keys
+	set.refreshed = time.Now()
+
+	return nil
+}
+
+// The file wins when both a file and a url are given
+func LoadKeySet(file string, url string) (*KeySet, error) {
+	set := &KeySet{
+		file: file,
+		url:  url,
+	}
+
+	err := set.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+/*
+A token without a key id can only be verified when there
+is just the one key to pick.
+*/
+func (set *KeySet) GetKey(id string) (crypto.PublicKey, bool) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	key, exists := set.lookup(id)
+
+	if exists || set.url == "" || set.file != "" {
+		return key, exists
+	}
+
+	if time.Since(set.refreshed) < keySetRefreshInterval {
+		return nil, false
+	}
+
+	err := set.load()
+
+	if err != nil {
+		// Keep using the keys we have and try again later
+		set.refreshed = time.Now()
+
+		return nil, false
+	}
+
+	return set.lookup(id)
+}
+
+func (set *KeySet) lookup(id string) (crypto.PublicKey, bool) {
+	if id == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+
+	key, exists := set.keys[id]
+
+	return key, exists
+}
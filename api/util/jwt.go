@@ -0,0 +1,164 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+/*
+The audience of a token can be a single string or a list
+of strings, both end up as a list.
+*/
+type TokenAudience []string
+
+func (audience *TokenAudience) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if json.Unmarshal(data, &single) == nil {
+		*audience = TokenAudience{single}
+		return nil
+	}
+
+	var list []string
+
+	err := json.Unmarshal(data, &list)
+
+	if err != nil {
+		return err
+	}
+
+	*audience = TokenAudience(list)
+
+	return nil
+}
+
+type TokenClaims struct {
+	Issuer    string        `json:"iss"`
+	Subject   string        `json:"sub"`
+	Audience  TokenAudience `json:"aud"`
+	Expires   *int64        `json:"exp"`
+	NotBefore *int64        `json:"nbf"`
+	Tenant    string        `json:"tenant"`
+	Roles     []string      `json:"roles"`
+}
+
+func verifySignature(
+	algorithm string,
+	key crypto.PublicKey,
+	signed []byte,
+	signature []byte,
+) error {
+
+	digest := sha256.Sum256(signed)
+
+	switch algorithm {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+
+		if !ok {
+			return errors.New("key can't be used for RS256")
+		}
+
+		return rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature)
+	case "ES256":
+		ecdsaKey, ok := key.(*ecdsa.PublicKey)
+
+		if !ok {
+			return errors.New("key can't be used for ES256")
+		}
+
+		if len(signature) != 64 {
+			return errors.New("invalid ES256 signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+
+		if !ecdsa.Verify(ecdsaKey, digest[:], r, s) {
+			return errors.New("invalid ES256 signature")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported algorithm (%s)", algorithm)
+}
+
+/*
+ParseToken checks the signature of a compact JWT against the
+key set and returns its claims. Only RS256 and ES256 are
+accepted so a token can never pick "none" or a shared
+secret for itself. The claims are not validated here.
+*/
+func ParseToken(token string, keys *KeySet) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, errors.New("malformed token header")
+	}
+
+	header := tokenHeader{}
+
+	err = json.Unmarshal(headerJSON, &header)
+
+	if err != nil {
+		return nil, errors.New("malformed token header")
+	}
+
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, fmt.Errorf("unsupported algorithm (%s)", header.Alg)
+	}
+
+	key, exists := keys.GetKey(header.Kid)
+
+	if !exists {
+		return nil, fmt.Errorf("unknown key (%s)", header.Kid)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+
+	err = verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+
+	claims := TokenClaims{}
+
+	err = json.Unmarshal(claimsJSON, &claims)
+
+	if err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,298 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var encode = base64.RawURLEncoding.EncodeToString
+
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		N:   encode(key.N.Bytes()),
+		E:   encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// P-256 coordinates and signature halves are always 32 bytes
+func pad32(value *big.Int) []byte {
+	bytes := value.Bytes()
+
+	return append(make([]byte, 32-len(bytes)), bytes...)
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   encode(pad32(key.X)),
+		Y:   encode(pad32(key.Y)),
+	}
+}
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+	set *KeySet
+}
+
+/*
+Writes a key set with one RSA and one EC key to a file and
+loads it the same way the api does on startup.
+*/
+func newTestKeys(t *testing.T) testKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(jsonWebKeySet{
+		Keys: []jsonWebKey{
+			rsaJWK("rsa-1", &rsaKey.PublicKey),
+			ecJWK("ec-1", &ecKey.PublicKey),
+		},
+	})
+
+	file, err := ioutil.TempFile("", "jwks")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	file.Write(data)
+	file.Close()
+
+	set, err := LoadKeySet(file.Name(), "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testKeys{rsa: rsaKey, ec: ecKey, set: set}
+}
+
+func signToken(
+	header map[string]string,
+	claims string,
+	sign func(signed []byte) []byte,
+) string {
+
+	headerJSON, _ := json.Marshal(header)
+	signed := encode(headerJSON) + "." + encode([]byte(claims))
+
+	return signed + "." + encode(sign([]byte(signed)))
+}
+
+func (keys testKeys) signRS256(signed []byte) []byte {
+	digest := sha256.Sum256(signed)
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, keys.rsa, crypto.SHA256, digest[:])
+
+	return signature
+}
+
+func (keys testKeys) signES256(signed []byte) []byte {
+	digest := sha256.Sum256(signed)
+	r, s, _ := ecdsa.Sign(rand.Reader, keys.ec, digest[:])
+
+	return append(pad32(r), pad32(s)...)
+}
+
+func TestParseToken(t *testing.T) {
+	keys := newTestKeys(t)
+
+	claims := `{"iss":"sso","sub":"user-1","aud":"api","exp":2000000000,"tenant":"acme","roles":["editor"]}`
+
+	publicKeyDER, _ := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+
+	hmacSign := func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, publicKeyDER)
+		mac.Write(signed)
+
+		return mac.Sum(nil)
+	}
+
+	noSignature := func(signed []byte) []byte {
+		return nil
+	}
+
+	valid := signToken(map[string]string{"alg": "RS256", "kid": "rsa-1"}, claims, keys.signRS256)
+	parts := strings.Split(valid, ".")
+	tampered := parts[0] + "." + encode([]byte(strings.Replace(claims, "acme", "evil", 1))) + "." + parts[2]
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"RS256", valid, true},
+		{
+			"ES256",
+			signToken(map[string]string{"alg": "ES256", "kid": "ec-1"}, claims, keys.signES256),
+			true,
+		},
+		{
+			"none is never accepted",
+			signToken(map[string]string{"alg": "none", "kid": "rsa-1"}, claims, noSignature),
+			false,
+		},
+		{
+			"HS256 signed with the public key",
+			signToken(map[string]string{"alg": "HS256", "kid": "rsa-1"}, claims, hmacSign),
+			false,
+		},
+		{
+			"RS256 header on an EC key",
+			signToken(map[string]string{"alg": "RS256", "kid": "ec-1"}, claims, keys.signRS256),
+			false,
+		},
+		{
+			"ES256 header on an RSA key",
+			signToken(map[string]string{"alg": "ES256", "kid": "rsa-1"}, claims, keys.signES256),
+			false,
+		},
+		{
+			"unknown key",
+			signToken(map[string]string{"alg": "RS256", "kid": "rsa-2"}, claims, keys.signRS256),
+			false,
+		},
+		{
+			"no key id with more than one key",
+			signToken(map[string]string{"alg": "RS256"}, claims, keys.signRS256),
+			false,
+		},
+		{"tampered claims", tampered, false},
+		{"missing signature", parts[0] + "." + parts[1], false},
+		{"garbage", "not.a.token", false},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseToken(test.token, keys.set)
+
+		if test.valid && err != nil {
+			t.Errorf("%s: ParseToken returned error %v", test.name, err)
+			continue
+		}
+
+		if !test.valid && err == nil {
+			t.Errorf("%s: ParseToken should have failed", test.name)
+			continue
+		}
+
+		if test.valid && (parsed.Subject != "user-1" || parsed.Tenant != "acme" || *parsed.Expires != 2000000000) {
+			t.Errorf("%s: ParseToken returned the wrong claims %+v", test.name, parsed)
+		}
+	}
+}
+
+func TestTokenAudience(t *testing.T) {
+	tests := []struct {
+		json  string
+		want  TokenAudience
+		valid bool
+	}{
+		{`"api"`, TokenAudience{"api"}, true},
+		{`["web","api"]`, TokenAudience{"web", "api"}, true},
+		{`[]`, TokenAudience{}, true},
+		{`42`, nil, false},
+		{`{"aud":"api"}`, nil, false},
+	}
+
+	for _, test := range tests {
+		var audience TokenAudience
+
+		err := json.Unmarshal([]byte(test.json), &audience)
+
+		if test.valid != (err == nil) {
+			t.Errorf("Unmarshal(%s) returned error %v", test.json, err)
+			continue
+		}
+
+		if test.valid && !reflect.DeepEqual(audience, test.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", test.json, audience, test.want)
+		}
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	smallRSAKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	offCurve := ecJWK("off-curve", &ecKey.PublicKey)
+	offCurve.Y = encode(make([]byte, 32))
+
+	encryption := rsaJWK("encryption", &rsaKey.PublicKey)
+	encryption.Use = "enc"
+
+	p384 := ecJWK("p384", &ecKey.PublicKey)
+	p384.Crv = "P-384"
+	p384.X = encode(p384Key.PublicKey.X.Bytes())
+	p384.Y = encode(p384Key.PublicKey.Y.Bytes())
+
+	badExponent := rsaJWK("bad-exponent", &rsaKey.PublicKey)
+	badExponent.E = encode([]byte{1})
+
+	tests := []struct {
+		key    jsonWebKey
+		usable bool
+	}{
+		{rsaJWK("rsa", &rsaKey.PublicKey), true},
+		{ecJWK("ec", &ecKey.PublicKey), true},
+		{rsaJWK("small-rsa", &smallRSAKey.PublicKey), false},
+		{badExponent, false},
+		{encryption, false},
+		{p384, false},
+		{offCurve, false},
+		{jsonWebKey{Kty: "oct", Kid: "secret"}, false},
+	}
+
+	for _, test := range tests {
+		data, _ := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{test.key}})
+
+		keys, err := parseKeySet(data)
+
+		if err != nil {
+			t.Errorf("parseKeySet(%s) returned error %v", test.key.Kid, err)
+			continue
+		}
+
+		_, exists := keys[test.key.Kid]
+
+		if exists != test.usable {
+			t.Errorf("parseKeySet(%s) kept the key = %v, want %v", test.key.Kid, exists, test.usable)
+		}
+	}
+
+	_, err := parseKeySet([]byte("not json"))
+
+	if err == nil {
+		t.Errorf("parseKeySet should fail on invalid JSON")
+	}
+}
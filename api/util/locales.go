@@ -0,0 +1,96 @@
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Turns an Accept-Language header like "sv-SE,sv;q=0.9,en;q=0.5"
+into the list of locales ordered by how much the client
+wants them. The wildcard and anything with a quality of
+zero is left out.
+*/
+func ParseAcceptLanguage(header string) []string {
+	type weightedLocale struct {
+		locale  string
+		quality float64
+	}
+
+	weighted := []weightedLocale{}
+
+	for _, part := range strings.Split(header, ",") {
+		pieces := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(pieces[0])
+		quality := 1.0
+
+		for _, parameter := range pieces[1:] {
+			parameter = strings.TrimSpace(parameter)
+
+			if strings.HasPrefix(parameter, "q=") {
+				parsed, err := strconv.ParseFloat(parameter[2:], 64)
+
+				if err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		if locale == "" || locale == "*" || quality <= 0 {
+			continue
+		}
+
+		weighted = append(weighted, weightedLocale{locale, quality})
+	}
+
+	sort.SliceStable(weighted, func(i int, j int) bool {
+		return weighted[i].quality > weighted[j].quality
+	})
+
+	locales := make([]string, len(weighted))
+
+	for i, locale := range weighted {
+		locales[i] = locale.locale
+	}
+
+	return locales
+}
+
+/*
+Looks up the first of the requested locales that there is a
+translation for. A locale with a region like "sv-SE" falls
+back to just its language "sv" before moving on to the next
+requested locale.
+*/
+func MatchLocale(
+	locales []string,
+	translations map[string]string,
+) (string, bool) {
+
+	lowerTranslations := map[string]string{}
+
+	for locale, translation := range translations {
+		lowerTranslations[strings.ToLower(locale)] = translation
+	}
+
+	for _, locale := range locales {
+		locale = strings.ToLower(locale)
+
+		translation, exists := lowerTranslations[locale]
+
+		if exists {
+			return translation, true
+		}
+
+		language := strings.SplitN(locale, "-", 2)[0]
+
+		translation, exists = lowerTranslations[language]
+
+		if exists {
+			return translation, true
+		}
+	}
+
+	return "", false
+}
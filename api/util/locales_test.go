@@ -0,0 +1,84 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"sv-SE", []string{"sv-SE"}},
+		{"sv-SE,sv;q=0.9,en;q=0.5", []string{"sv-SE", "sv", "en"}},
+		{"en;q=0.5, sv;q=0.9, de", []string{"de", "sv", "en"}},
+
+		// Equal qualities keep the order of the header
+		{"fi;q=0.8,da;q=0.8", []string{"fi", "da"}},
+
+		// Wildcards and locales that aren't wanted are left out
+		{"*,en;q=0", []string{}},
+		{"en, *;q=0.1, fr;q=0", []string{"en"}},
+
+		// A quality that can't be parsed counts as 1
+		{"en;q=abc,sv;q=0.5", []string{"en", "sv"}},
+		{" en-GB ; q=0.7 ,,nb", []string{"nb", "en-GB"}},
+	}
+
+	for _, test := range tests {
+		got := ParseAcceptLanguage(test.header)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", test.header, got, test.want)
+		}
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	translations := map[string]string{
+		"sv":    "Röd",
+		"en-GB": "Red",
+		"EN-us": "Red (US)",
+	}
+
+	tests := []struct {
+		locales []string
+		want    string
+		found   bool
+	}{
+		{[]string{"sv"}, "Röd", true},
+
+		// Regions fall back to the language
+		{[]string{"sv-FI"}, "Röd", true},
+
+		// Locales are matched without caring about case
+		{[]string{"en-gb"}, "Red", true},
+		{[]string{"en-US"}, "Red (US)", true},
+
+		// The first requested locale that matches wins
+		{[]string{"de", "fr", "sv-SE", "en-GB"}, "Röd", true},
+		{[]string{"en-GB", "sv"}, "Red", true},
+
+		// A language doesn't match any of its regions
+		{[]string{"en"}, "", false},
+		{[]string{"de", "fr"}, "", false},
+		{[]string{}, "", false},
+	}
+
+	for _, test := range tests {
+		got, found := MatchLocale(test.locales, translations)
+
+		if got != test.want || found != test.found {
+			t.Errorf(
+				"MatchLocale(%v) = %q, %v, want %q, %v",
+				test.locales,
+				got,
+				found,
+				test.want,
+				test.found,
+			)
+		}
+	}
+}
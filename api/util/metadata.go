@@ -1,13 +1,17 @@
 package util
 
-/*
-Currently the metadata only has a RequestID which is handy
-for tracking logs.
+// Requests that don't name a tenant belong to the default one
+const DefaultTenant = "default"
 
-In a production service we would probably have more information
-here as for example the user that is actually making
-the request.
+/*
+The request id is handy for tracking logs and the tenant
+decides which catalogue every repository reads and writes.
+The identity says who is making the request, for example
+the api key that was used, and the roles what they may do.
 */
 type Metadata struct {
 	RequestID uint32
+	Tenant    string
+	Identity  string
+	Roles     []string
 }
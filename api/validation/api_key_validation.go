@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func GetApiKeyNotFoundError(id uint64) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find api key %v", id),
+	}
+}
+
+func GetUnauthorizedError(message string) error {
+	return UnauthorizedError{
+		message: message,
+	}
+}
+
+func ValidateApiKey(input domain.ApiKeyInput) error {
+
+	if len(input.Name) == 0 {
+		return errors.New("Api key name can not be empty")
+	}
+
+	if len(input.Name) > 64 {
+		return fmt.Errorf("Api key name (%s) is longer than max of 64 characters", input.Name)
+	}
+
+	return ValidateRoles(input.Roles)
+}
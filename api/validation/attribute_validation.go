@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func GetAttributeDefinitionNotFoundError(name string) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find attribute definition (%s)", name),
+	}
+}
+
+func GetAttributeDefinitionExistsError(name string) error {
+	return fmt.Errorf("Attribute definition (%s) already exists", name)
+}
+
+func validateAttributeValue(
+	definition domain.AttributeDefinition,
+	value string,
+) error {
+
+	var err error
+
+	switch definition.DataType {
+	case domain.AttributeTypeInteger:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case domain.AttributeTypeDecimal:
+		_, err = strconv.ParseFloat(value, 64)
+	case domain.AttributeTypeBoolean:
+		if value != "true" && value != "false" {
+			err = errors.New("Not a boolean")
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Attribute (%s) value (%s) is not a valid %s", definition.Name, value, definition.DataType)
+	}
+
+	if len(definition.AllowedValues) == 0 {
+		return nil
+	}
+
+	for _, allowedValue := range definition.AllowedValues {
+		if allowedValue == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Attribute (%s) value (%s) is not one of the allowed values", definition.Name, value)
+}
+
+func ValidateAttributeDefinition(definition domain.AttributeDefinition) error {
+
+	if len(definition.Name) == 0 {
+		return errors.New("Attribute name can not be empty")
+	}
+
+	if len(definition.Name) > 16 {
+		return fmt.Errorf("Attribute name (%s) is longer than max of 16 characters", definition.Name)
+	}
+
+	switch definition.DataType {
+	case domain.AttributeTypeString,
+		domain.AttributeTypeInteger,
+		domain.AttributeTypeDecimal,
+		domain.AttributeTypeBoolean:
+	default:
+		return fmt.Errorf("Unknown attribute data type (%s)", definition.DataType)
+	}
+
+	if definition.Unit != nil && len(*definition.Unit) > 16 {
+		return fmt.Errorf("Attribute unit (%s) is longer than max of 16 characters", *definition.Unit)
+	}
+
+	valueSet := map[string]struct{}{}
+
+	for _, value := range definition.AllowedValues {
+		if len(value) > 32 {
+			return fmt.Errorf("Attribute value (%s) is longer than max of 32 characters", value)
+		}
+
+		withoutAllowedValues := definition
+		withoutAllowedValues.AllowedValues = nil
+
+		err := validateAttributeValue(withoutAllowedValues, value)
+
+		if err != nil {
+			return err
+		}
+
+		valueSet[value] = struct{}{}
+	}
+
+	if len(valueSet) < len(definition.AllowedValues) {
+		return errors.New("Allowed values not unique")
+	}
+
+	for value, labels := range definition.ValueLabels {
+		_, allowed := valueSet[value]
+
+		if !allowed {
+			return fmt.Errorf("Can't add labels to value (%s) since it is not one of the allowed values", value)
+		}
+
+		for locale, label := range labels {
+			err := validateLocale(locale)
+
+			if err != nil {
+				return err
+			}
+
+			if len(label) == 0 || len(label) > 64 {
+				return fmt.Errorf("Label for value (%s) in locale (%s) has to be between 1 and 64 characters", value, locale)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func GetBrandNotFoundError(id domain.BrandId) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find brand %v", id),
+	}
+}
+
+func GetBrandExistsError(name string) error {
+	return fmt.Errorf("Brand (%s) already exists", name)
+}
+
+func ValidateBrand(brand domain.BrandInput) error {
+
+	if len(brand.Name) == 0 {
+		return errors.New("Brand name can not be empty")
+	}
+
+	if len(brand.Name) > 64 {
+		return fmt.Errorf("Brand name (%s) is longer than max of 64 characters", brand.Name)
+	}
+
+	if brand.Manufacturer != nil && len(*brand.Manufacturer) > 64 {
+		return fmt.Errorf("Manufacturer (%s) is longer than max of 64 characters", *brand.Manufacturer)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func validateBundleComponents(components []domain.BundleComponent) error {
+
+	if len(components) > 50 {
+		return errors.New("Too many bundle components, max is 50")
+	}
+
+	componentSet := map[domain.ProductId]struct{}{}
+
+	for _, component := range components {
+		if component.Quantity == 0 || component.Quantity > 10000 {
+			return fmt.Errorf("Quantity of component %v has to be between 1 and 10000", component.ProductID)
+		}
+
+		componentSet[component.ProductID] = struct{}{}
+	}
+
+	if len(componentSet) < len(components) {
+		return errors.New("Bundle components not unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func GetCategoryNotFoundError(id domain.CategoryId) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find category %v", id),
+	}
+}
+
+func ValidateCategory(category domain.CategoryInput) error {
+
+	if len(category.Name) == 0 {
+		return errors.New("Category name can not be empty")
+	}
+
+	if len(category.Name) > 64 {
+		return fmt.Errorf("Category name (%s) is longer than max of 64 characters", category.Name)
+	}
+
+	if category.SortOrder < 0 {
+		return errors.New("Category sort order can not be negative")
+	}
+
+	return nil
+}
+
+func validateCategoryIDs(categoryIDs []domain.CategoryId) error {
+
+	if len(categoryIDs) > 20 {
+		return errors.New("Too many categories, max is 20")
+	}
+
+	categorySet := map[domain.CategoryId]struct{}{}
+
+	for _, categoryID := range categoryIDs {
+		categorySet[categoryID] = struct{}{}
+	}
+
+	if len(categorySet) < len(categoryIDs) {
+		return errors.New("Categories not unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func GetChannelNotFoundError(code string) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find channel (%s)", code),
+	}
+}
+
+func GetChannelExistsError(code string) error {
+	return fmt.Errorf("Channel (%s) already exists", code)
+}
+
+// Channel codes end up in urls and query strings
+func validateChannelCode(code string) error {
+
+	if len(code) == 0 {
+		return errors.New("Channel code can not be empty")
+	}
+
+	if len(code) > 16 {
+		return fmt.Errorf("Channel code (%s) is longer than max of 16 characters", code)
+	}
+
+	for _, character := range code {
+		isLetter := character >= 'a' && character <= 'z'
+		isDigit := character >= '0' && character <= '9'
+
+		if !isLetter && !isDigit && character != '-' {
+			return fmt.Errorf("Channel code (%s) can only contain lower case letters, digits and dashes", code)
+		}
+	}
+
+	return nil
+}
+
+func ValidateChannelCode(code string) error {
+	return validateChannelCode(code)
+}
+
+func ValidateChannel(channel domain.Channel) error {
+
+	err := validateChannelCode(channel.Code)
+
+	if err != nil {
+		return err
+	}
+
+	if len(channel.Name) == 0 {
+		return errors.New("Channel name can not be empty")
+	}
+
+	if len(channel.Name) > 64 {
+		return fmt.Errorf("Channel name (%s) is longer than max of 64 characters", channel.Name)
+	}
+
+	return nil
+}
+
+/*
+The overrides follow the same rules as the title and price
+of the product itself and every channel can only be listed
+once.
+*/
+func ValidateProductChannels(channels []domain.ProductChannel) error {
+
+	channelSet := map[string]struct{}{}
+
+	for _, channel := range channels {
+		err := validateChannelCode(channel.Channel)
+
+		if err != nil {
+			return err
+		}
+
+		if channel.Title != nil {
+			err := validateTitle(*channel.Title)
+
+			if err != nil {
+				return fmt.Errorf("Channel (%s): %s", channel.Channel, err.Error())
+			}
+		}
+
+		if channel.Price != nil {
+			err := validatePrice(*channel.Price)
+
+			if err != nil {
+				return fmt.Errorf("Channel (%s): %s", channel.Channel, err.Error())
+			}
+		}
+
+		channelSet[channel.Channel] = struct{}{}
+	}
+
+	if len(channelSet) < len(channels) {
+		return errors.New("Channels not unique")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+var allowedLabelFields = map[string]struct{}{
+	"title":       struct{}{},
+	"sku":         struct{}{},
+	"description": struct{}{},
+	"price":       struct{}{},
+	"barcode":     struct{}{},
+}
+
+func validateLabelTemplate(template domain.LabelTemplate) error {
+
+	if template.Width < 10 || template.Width > 300 {
+		return errors.New("Label width has to be between 10 and 300 millimeters")
+	}
+
+	if template.Height < 10 || template.Height > 300 {
+		return errors.New("Label height has to be between 10 and 300 millimeters")
+	}
+
+	if len(template.Fields) == 0 {
+		return errors.New("Label template needs at least one field")
+	}
+
+	for _, field := range template.Fields {
+		_, ok := allowedLabelFields[field]
+
+		if !ok {
+			return fmt.Errorf("Unknown label field (%s)", field)
+		}
+	}
+
+	for field, fontSize := range template.FontSizes {
+		_, ok := allowedLabelFields[field]
+
+		if !ok || field == "barcode" {
+			return fmt.Errorf("Can't set a font size for label field (%s)", field)
+		}
+
+		if fontSize < 4 || fontSize > 72 {
+			return fmt.Errorf("Font size for label field (%s) has to be between 4 and 72", field)
+		}
+	}
+
+	if template.BarcodeHeight < 0 || template.BarcodeHeight > template.Height {
+		return errors.New("Barcode height can not be bigger than the label")
+	}
+
+	return nil
+}
+
+func ValidateLabelInput(input domain.LabelInput) error {
+
+	if len(input.ProductIDs) == 0 {
+		return errors.New("No products to print labels for")
+	}
+
+	if len(input.ProductIDs) > 500 {
+		return errors.New("Can't print more than 500 labels at once")
+	}
+
+	if input.Locale != "" {
+		err := validateLocale(input.Locale)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateLabelTemplate(input.Template)
+}
@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"fmt"
+)
+
+/*
+Locales are language tags like "en" or "sv-SE". We only
+check that they look like one, not that the language
+actually exists.
+*/
+func validateLocale(locale string) error {
+
+	if len(locale) < 2 || len(locale) > 16 {
+		return fmt.Errorf("Locale (%s) has to be between 2 and 16 characters", locale)
+	}
+
+	for _, character := range locale {
+		isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		isDigit := character >= '0' && character <= '9'
+
+		if !isLetter && !isDigit && character != '-' {
+			return fmt.Errorf("Locale (%s) is not a valid language tag", locale)
+		}
+	}
+
+	return nil
+}
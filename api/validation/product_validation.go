@@ -2,6 +2,7 @@ package validation
 
 import (
 	"api/domain"
+	"api/util"
 	"errors"
 	"fmt"
 	"math"
@@ -30,6 +31,155 @@ func GetSkuAlreadyExistsError(sku string) error {
 	return fmt.Errorf("SKU '%s' already exists", sku)
 }
 
+/*
+The server responds with a 404 instead of a 400 when it
+receives this error.
+*/
+type NotFoundError struct {
+	message string
+}
+
+func (err NotFoundError) Error() string {
+	return err.message
+}
+
+/*
+The server answers with a 401 whenever the service or the
+authentication middleware returns this error.
+*/
+type UnauthorizedError struct {
+	message string
+}
+
+func (err UnauthorizedError) Error() string {
+	return err.message
+}
+
+/*
+The caller is known but is not allowed to do what it asked
+for. The code tells clients which rule was broken without
+having to parse the message, the server answers with a 403.
+*/
+type ForbiddenError struct {
+	code    string
+	message string
+}
+
+func (err ForbiddenError) Error() string {
+	return err.message
+}
+
+func (err ForbiddenError) Code() string {
+	return err.code
+}
+
+func GetBarcodeNotFoundError(barcode string) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find a product with barcode (%s)", barcode),
+	}
+}
+
+func ValidateBarcode(barcode string) error {
+
+	if len(barcode) == 0 {
+		return errors.New("Barcode can not be empty")
+	}
+
+	if len(barcode) > 32 {
+		return fmt.Errorf("Barcode (%s) is longer than max of 32 characters", barcode)
+	}
+
+	return nil
+}
+
+/*
+Strict barcode validation is turned on per deployment and
+only lets through barcodes in one of the GS1 formats with
+a correct check digit.
+*/
+func ValidateGS1Barcodes(barcodes []string) error {
+
+	for _, barcode := range barcodes {
+		if util.DetectBarcodeType(barcode) == util.BarcodeTypeOther {
+			return fmt.Errorf("Barcode (%s) is not a valid EAN-8, EAN-13, UPC-A, UPC-E, GTIN-14 or ITF-14 barcode", barcode)
+		}
+	}
+
+	return nil
+}
+
+/*
+A generated barcode is an EAN-13 barcode so the prefix has
+to leave room for at least one digit of item reference
+before the check digit. Only numbers that are ours to hand
+out are allowed: the in-store range 20-29, which never leaves
+the store, or the GS1 company prefix of the retailer. Anything
+else would collide with barcodes of other companies, books
+(978/979) or UPC products (0).
+*/
+func ValidateBarcodePrefix(prefix string, companyPrefix string) error {
+
+	if prefix == "" {
+		return errors.New("Barcode generation is not configured")
+	}
+
+	if len(prefix) < 2 || len(prefix) > 11 {
+		return fmt.Errorf("Barcode prefix (%s) has to be between 2 and 11 digits", prefix)
+	}
+
+	_, err := strconv.ParseUint(prefix, 10, 64)
+
+	if err != nil {
+		return fmt.Errorf("Barcode prefix (%s) can only contain digits", prefix)
+	}
+
+	if prefix[0] == '2' {
+		return nil
+	}
+
+	if companyPrefix == "" || !strings.HasPrefix(prefix, companyPrefix) {
+		return fmt.Errorf(
+			"Barcode prefix (%s) has to be an in-store prefix (20-29) or start with the GS1 company prefix",
+			prefix,
+		)
+	}
+
+	if prefix[0] == '0' || strings.HasPrefix(prefix, "977") ||
+		strings.HasPrefix(prefix, "978") || strings.HasPrefix(prefix, "979") {
+
+		return fmt.Errorf("Barcode prefix (%s) is reserved and can't be a GS1 company prefix", prefix)
+	}
+
+	return nil
+}
+
+func ValidateBarcodeImageOptions(options domain.BarcodeImageOptions) error {
+
+	if options.Format != "svg" && options.Format != "png" {
+		return fmt.Errorf("Unknown image format (%s), expected svg or png", options.Format)
+	}
+
+	switch options.Symbology {
+	case "", "ean13", "upca", "code128", "qr":
+	default:
+		return fmt.Errorf("Unknown symbology (%s), expected ean13, upca, code128 or qr", options.Symbology)
+	}
+
+	if options.ModuleSize < 0 || options.ModuleSize > 20 {
+		return errors.New("Module size has to be between 1 and 20")
+	}
+
+	if options.BarHeight < 0 || options.BarHeight > 1000 {
+		return errors.New("Bar height has to be between 1 and 1000")
+	}
+
+	if options.QuietZone != nil && (*options.QuietZone < 0 || *options.QuietZone > 50) {
+		return errors.New("Quiet zone has to be between 0 and 50")
+	}
+
+	return nil
+}
+
 func validateBarcodes(barcodes []string) error {
 
 	if len(barcodes) > 0 {
@@ -51,7 +201,24 @@ func validateBarcodes(barcodes []string) error {
 	return nil
 }
 
-func validateAttributes(attributes []domain.ProductAttribute) error {
+/*
+Attributes are checked against the definition with the same
+name if there is one. Required attributes are only checked
+when the whole attribute list is being replaced.
+*/
+func validateAttributes(
+	attributes []domain.ProductAttribute,
+	definitions []domain.AttributeDefinition,
+	checkRequired bool,
+) error {
+
+	definitionMap := map[string]domain.AttributeDefinition{}
+
+	for _, definition := range definitions {
+		definitionMap[strings.ToLower(definition.Name)] = definition
+	}
+
+	presentSet := map[string]struct{}{}
 
 	if len(attributes) > 0 {
 		attributeSet := map[string]struct{}{}
@@ -69,6 +236,22 @@ func validateAttributes(attributes []domain.ProductAttribute) error {
 				return fmt.Errorf("Attribute value (%s) is longer than max of 32 characters", attribute.Value)
 			}
 
+			definition, hasDefinition := definitionMap[strings.ToLower(attribute.Name)]
+
+			if hasDefinition {
+				if definition.Name != attribute.Name {
+					return fmt.Errorf("Attribute (%s) has to be written as (%s)", attribute.Name, definition.Name)
+				}
+
+				err := validateAttributeValue(definition, attribute.Value)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			presentSet[strings.ToLower(attribute.Name)] = struct{}{}
+
 			hash := getAttributeHash(attribute)
 			attributeSet[hash] = struct{}{}
 		}
@@ -78,6 +261,16 @@ func validateAttributes(attributes []domain.ProductAttribute) error {
 		}
 	}
 
+	if checkRequired {
+		for _, definition := range definitions {
+			_, present := presentSet[strings.ToLower(definition.Name)]
+
+			if definition.Required && !present {
+				return fmt.Errorf("Attribute (%s) is required", definition.Name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -115,6 +308,52 @@ func validateDescription(description string) error {
 	return nil
 }
 
+/*
+Translations follow the same length limits as the fields
+on the product itself. Locales only differing in case would
+end up as the same row so they count as duplicates.
+*/
+func validateTranslations(translations map[string]domain.ProductTranslation) error {
+
+	if len(translations) > 20 {
+		return errors.New("Too many translations, max is 20")
+	}
+
+	localeSet := map[string]struct{}{}
+
+	for locale, translation := range translations {
+		err := validateLocale(locale)
+
+		if err != nil {
+			return err
+		}
+
+		localeSet[strings.ToLower(locale)] = struct{}{}
+
+		if translation.Title != nil {
+			err := validateTitle(*translation.Title)
+
+			if err != nil {
+				return fmt.Errorf("Translation (%s): %s", locale, err.Error())
+			}
+		}
+
+		if translation.Description != nil {
+			err := validateDescription(*translation.Description)
+
+			if err != nil {
+				return fmt.Errorf("Translation (%s): %s", locale, err.Error())
+			}
+		}
+	}
+
+	if len(localeSet) < len(translations) {
+		return errors.New("Translation locales not unique")
+	}
+
+	return nil
+}
+
 func validatePrice(price string) error {
 
 	float, err := strconv.ParseFloat(price, 10)
@@ -134,6 +373,50 @@ func validatePrice(price string) error {
 	return nil
 }
 
+func ValidateSuggestionPrefix(prefix string) error {
+
+	if len(prefix) == 0 {
+		return errors.New("Prefix can not be empty")
+	}
+
+	if len(prefix) > 32 {
+		return fmt.Errorf("Prefix (%s) is longer than max of 32 characters", prefix)
+	}
+
+	return nil
+}
+
+/*
+The buckets are the lower bounds of each price range so
+they have to be valid prices in strictly increasing order.
+*/
+func ValidatePriceBuckets(priceBuckets []string) error {
+
+	if len(priceBuckets) > 20 {
+		return errors.New("Too many price buckets, max is 20")
+	}
+
+	previous := -1.0
+
+	for _, bucket := range priceBuckets {
+		err := validatePrice(bucket)
+
+		if err != nil {
+			return err
+		}
+
+		float, _ := strconv.ParseFloat(bucket, 64)
+
+		if float <= previous {
+			return fmt.Errorf("Price bucket (%s) has to be bigger than the previous bucket", bucket)
+		}
+
+		previous = float
+	}
+
+	return nil
+}
+
 /*
 Probably one of the most important parts of the validation.
 
@@ -148,12 +431,21 @@ func ValidateFields(fields []string) error {
 	allowedFields["productId"] = struct{}{}
 	allowedFields["title"] = struct{}{}
 	allowedFields["sku"] = struct{}{}
+	allowedFields["status"] = struct{}{}
 	allowedFields["barcodes"] = struct{}{}
 	allowedFields["description"] = struct{}{}
 	allowedFields["attributes"] = struct{}{}
 	allowedFields["price"] = struct{}{}
 	allowedFields["created"] = struct{}{}
 	allowedFields["lastUpdated"] = struct{}{}
+	allowedFields["translations"] = struct{}{}
+	allowedFields["parentId"] = struct{}{}
+	allowedFields["variantAxes"] = struct{}{}
+	allowedFields["categoryIds"] = struct{}{}
+	allowedFields["brandId"] = struct{}{}
+	allowedFields["relations"] = struct{}{}
+	allowedFields["components"] = struct{}{}
+	allowedFields["stock"] = struct{}{}
 
 	for _, field := range fields {
 		_, ok := allowedFields[field]
@@ -166,7 +458,10 @@ func ValidateFields(fields []string) error {
 	return nil
 }
 
-func ValidateProductUpdate(changes domain.ProductUpdateInput) error {
+func ValidateProductUpdate(
+	changes domain.ProductUpdateInput,
+	definitions []domain.AttributeDefinition,
+) error {
 
 	if changes.Title != nil {
 
@@ -201,13 +496,45 @@ func ValidateProductUpdate(changes domain.ProductUpdateInput) error {
 		}
 	}
 
+	if changes.Status != nil {
+		err := validateStatus(*changes.Status)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	err := validateBarcodes(changes.Barcodes)
 
 	if err != nil {
 		return err
 	}
 
-	err = validateAttributes(changes.Attributes)
+	err = validateAttributes(changes.Attributes, definitions, changes.Attributes != nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateTranslations(changes.Translations)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateVariantAxes(changes.VariantAxes)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateCategoryIDs(changes.CategoryIDs)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateBundleComponents(changes.Components)
 
 	if err != nil {
 		return err
@@ -216,7 +543,10 @@ func ValidateProductUpdate(changes domain.ProductUpdateInput) error {
 	return nil
 }
 
-func ValidateNewProduct(product domain.ProductAddInput) error {
+func ValidateNewProduct(
+	product domain.ProductAddInput,
+	definitions []domain.AttributeDefinition,
+) error {
 
 	err := validateTitle(product.Title)
 
@@ -246,13 +576,49 @@ func ValidateNewProduct(product domain.ProductAddInput) error {
 		}
 	}
 
+	if product.Status != nil {
+		err := validateNewStatus(*product.Status)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	err = validateBarcodes(product.Barcodes)
 
 	if err != nil {
 		return err
 	}
 
-	err = validateAttributes(product.Attributes)
+	err = validateAttributes(product.Attributes, definitions, true)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateTranslations(product.Translations)
+
+	if err != nil {
+		return err
+	}
+
+	if product.ParentID != nil && len(product.VariantAxes) > 0 {
+		return errors.New("A variant can't have variant axes of its own")
+	}
+
+	err = validateVariantAxes(product.VariantAxes)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateCategoryIDs(product.CategoryIDs)
+
+	if err != nil {
+		return err
+	}
+
+	err = validateBundleComponents(product.Components)
 
 	if err != nil {
 		return err
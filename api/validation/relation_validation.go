@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+var relationTypes = map[string]struct{}{
+	domain.RelationTypeAccessoryOf: struct{}{},
+	domain.RelationTypeReplacedBy:  struct{}{},
+	domain.RelationTypeCrossSell:   struct{}{},
+	domain.RelationTypeUpSell:      struct{}{},
+}
+
+func ValidateRelationType(relationType string) error {
+	_, ok := relationTypes[relationType]
+
+	if !ok {
+		return fmt.Errorf(
+			"Unknown relation type (%s), expected accessory-of, replaced-by, cross-sell or up-sell",
+			relationType,
+		)
+	}
+
+	return nil
+}
+
+/*
+A product can't be related to itself and can only be
+related to another product once per relation type.
+*/
+func ValidateRelations(
+	id domain.ProductId,
+	relations []domain.ProductRelation,
+) error {
+
+	if len(relations) > 100 {
+		return errors.New("Too many relations, max is 100")
+	}
+
+	relationSet := map[string]struct{}{}
+
+	for _, relation := range relations {
+		err := ValidateRelationType(relation.Type)
+
+		if err != nil {
+			return err
+		}
+
+		if relation.ProductID == id {
+			return errors.New("A product can't be related to itself")
+		}
+
+		if relation.SortOrder < 0 {
+			return errors.New("Relation sort order can not be negative")
+		}
+
+		key := fmt.Sprintf("%s_%v", relation.Type, relation.ProductID)
+		relationSet[key] = struct{}{}
+	}
+
+	if len(relationSet) < len(relations) {
+		return errors.New("Relations not unique")
+	}
+
+	return nil
+}
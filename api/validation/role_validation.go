@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+const (
+	ErrorCodeEditor       = "editor_required"
+	ErrorCodePriceManager = "price_manager_required"
+	ErrorCodeAdmin        = "admin_required"
+)
+
+var roles = map[string]struct{}{
+	domain.RoleViewer:       struct{}{},
+	domain.RoleEditor:       struct{}{},
+	domain.RolePriceManager: struct{}{},
+	domain.RoleAdmin:        struct{}{},
+}
+
+func GetForbiddenError(code string, message string) error {
+	return ForbiddenError{
+		code:    code,
+		message: message,
+	}
+}
+
+func ValidateRoles(candidates []string) error {
+
+	roleSet := map[string]struct{}{}
+
+	for _, role := range candidates {
+		_, ok := roles[role]
+
+		if !ok {
+			return fmt.Errorf(
+				"Unknown role (%s), expected viewer, editor, price-manager or admin",
+				role,
+			)
+		}
+
+		roleSet[role] = struct{}{}
+	}
+
+	if len(roleSet) < len(candidates) {
+		return errors.New("Roles not unique")
+	}
+
+	return nil
+}
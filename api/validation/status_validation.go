@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"api/domain"
+	"fmt"
+)
+
+/*
+Products only ever move forward through their lifecycle, the
+only way back is reactivating a discontinued product or
+restoring an archived one as a draft.
+*/
+var allowedStatusTransitions = map[string][]string{
+	domain.ProductStatusDraft:        {domain.ProductStatusActive, domain.ProductStatusArchived},
+	domain.ProductStatusActive:       {domain.ProductStatusDiscontinued, domain.ProductStatusArchived},
+	domain.ProductStatusDiscontinued: {domain.ProductStatusActive, domain.ProductStatusArchived},
+	domain.ProductStatusArchived:     {domain.ProductStatusDraft},
+}
+
+func validateStatus(status string) error {
+
+	_, ok := allowedStatusTransitions[status]
+
+	if !ok {
+		return fmt.Errorf(
+			"Unknown status (%s), expected draft, active, discontinued or archived",
+			status,
+		)
+	}
+
+	return nil
+}
+
+func validateNewStatus(status string) error {
+
+	if status != domain.ProductStatusDraft && status != domain.ProductStatusActive {
+		return fmt.Errorf("A new product has to be draft or active, not (%s)", status)
+	}
+
+	return nil
+}
+
+func ValidateStatuses(statuses []string) error {
+
+	for _, status := range statuses {
+		err := validateStatus(status)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Setting the status a product already has is not a transition
+func ValidateStatusTransition(from string, to string) error {
+
+	if from == to {
+		return nil
+	}
+
+	for _, allowed := range allowedStatusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Can't change status of product from (%s) to (%s)", from, to)
+}
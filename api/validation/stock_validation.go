@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+/*
+Location codes are picked by whoever sets up the warehouses
+and stores so we only make sure they are short and safe to
+put in a url.
+*/
+func validateLocation(location string) error {
+
+	if len(location) == 0 {
+		return errors.New("Location can not be empty")
+	}
+
+	if len(location) > 32 {
+		return fmt.Errorf("Location (%s) is longer than max of 32 characters", location)
+	}
+
+	for _, character := range location {
+		isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		isDigit := character >= '0' && character <= '9'
+
+		if !isLetter && !isDigit && character != '-' && character != '_' {
+			return fmt.Errorf("Location (%s) can only contain letters, digits, dashes and underscores", location)
+		}
+	}
+
+	return nil
+}
+
+/*
+Sales can only take stock away and returns and receipts can
+only add to it. A transfer always moves a positive quantity
+away from the location towards the target location.
+*/
+func ValidateStockAdjustment(adjustment domain.StockAdjustment) error {
+
+	err := validateLocation(adjustment.Location)
+
+	if err != nil {
+		return err
+	}
+
+	if adjustment.Delta == 0 {
+		return errors.New("Stock adjustment can not be zero")
+	}
+
+	if adjustment.Delta > 1000000 || adjustment.Delta < -1000000 {
+		return errors.New("Stock adjustment has to be between -1000000 and 1000000")
+	}
+
+	if adjustment.Reference != nil && len(*adjustment.Reference) > 64 {
+		return fmt.Errorf("Reference (%s) is longer than max of 64 characters", *adjustment.Reference)
+	}
+
+	if adjustment.Reason != domain.StockReasonTransfer && adjustment.ToLocation != "" {
+		return errors.New("Only a transfer can have a target location")
+	}
+
+	switch adjustment.Reason {
+	case domain.StockReasonSale:
+		if adjustment.Delta > 0 {
+			return errors.New("A sale can only take stock away")
+		}
+	case domain.StockReasonReturn, domain.StockReasonReceipt:
+		if adjustment.Delta < 0 {
+			return fmt.Errorf("A %s can only add stock", adjustment.Reason)
+		}
+	case domain.StockReasonTransfer:
+		err := validateLocation(adjustment.ToLocation)
+
+		if err != nil {
+			return err
+		}
+
+		if adjustment.ToLocation == adjustment.Location {
+			return errors.New("Can't transfer stock to the same location")
+		}
+
+		if adjustment.Delta < 0 {
+			return errors.New("The quantity of a transfer can not be negative")
+		}
+	case domain.StockReasonCount:
+	default:
+		return fmt.Errorf(
+			"Unknown stock reason (%s), expected sale, return, receipt, transfer or count",
+			adjustment.Reason,
+		)
+	}
+
+	return nil
+}
+
+func GetReservationNotFoundError(id uint64) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find stock reservation %v", id),
+	}
+}
+
+func ValidateStockReservation(reservation domain.StockReservationInput) error {
+
+	err := validateLocation(reservation.Location)
+
+	if err != nil {
+		return err
+	}
+
+	if reservation.Quantity < 1 || reservation.Quantity > 1000000 {
+		return errors.New("Reserved quantity has to be between 1 and 1000000")
+	}
+
+	if reservation.TTLSeconds > 86400 {
+		return errors.New("A reservation can't be held for longer than 86400 seconds")
+	}
+
+	if reservation.Reference != nil && len(*reservation.Reference) > 64 {
+		return fmt.Errorf("Reference (%s) is longer than max of 64 characters", *reservation.Reference)
+	}
+
+	return nil
+}
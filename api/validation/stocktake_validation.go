@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+)
+
+func GetStocktakeNotFoundError(id uint64) error {
+	return NotFoundError{
+		message: fmt.Sprintf("Can't find stocktake %v", id),
+	}
+}
+
+func ValidateStocktakeLocation(location string) error {
+	return validateLocation(location)
+}
+
+/*
+A handheld scanner posts one scan per beep but a counter with
+a spreadsheet posts a quantity per barcode so both are fine.
+*/
+func ValidateStocktakeScans(scans []domain.StocktakeScan) error {
+
+	if len(scans) == 0 {
+		return errors.New("A stocktake needs at least one scan")
+	}
+
+	if len(scans) > 1000 {
+		return errors.New("Can't post more than 1000 scans at once")
+	}
+
+	for _, scan := range scans {
+		if len(scan.Barcode) == 0 {
+			return errors.New("Scanned barcode can not be empty")
+		}
+
+		if scan.Quantity < 0 || scan.Quantity > 1000000 {
+			return fmt.Errorf("Counted quantity of (%s) has to be between 0 and 1000000", scan.Barcode)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+)
+
+func ValidateTenant(tenant string) error {
+
+	if len(tenant) == 0 {
+		return errors.New("Tenant can not be empty")
+	}
+
+	if len(tenant) > 32 {
+		return fmt.Errorf("Tenant (%s) is longer than max of 32 characters", tenant)
+	}
+
+	for _, character := range tenant {
+		isLetter := character >= 'a' && character <= 'z'
+		isDigit := character >= '0' && character <= '9'
+
+		if !isLetter && !isDigit && character != '-' && character != '_' {
+			return fmt.Errorf("Tenant (%s) can only contain lower case letters, digits, dashes and underscores", tenant)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"api/util"
+)
+
+// Allowed difference between our clock and the one of the issuer
+const tokenLeewaySeconds = 60
+
+/*
+Tokens have to be issued for us by the configured issuer
+and have to expire. Any tenant in the token has to be a
+valid tenant name since it ends up in every query.
+*/
+func ValidateTokenClaims(
+	claims util.TokenClaims,
+	issuer string,
+	audience string,
+	now int64,
+) error {
+
+	if claims.Issuer != issuer {
+		return GetUnauthorizedError("Token was issued by an unknown issuer")
+	}
+
+	audienceMatches := false
+
+	for _, candidate := range claims.Audience {
+		if candidate == audience {
+			audienceMatches = true
+		}
+	}
+
+	if !audienceMatches {
+		return GetUnauthorizedError("Token was not issued for this api")
+	}
+
+	if claims.Expires == nil {
+		return GetUnauthorizedError("Token has no expiry")
+	}
+
+	if *claims.Expires+tokenLeewaySeconds < now {
+		return GetUnauthorizedError("Token has expired")
+	}
+
+	if claims.NotBefore != nil && *claims.NotBefore-tokenLeewaySeconds > now {
+		return GetUnauthorizedError("Token is not valid yet")
+	}
+
+	if claims.Subject == "" {
+		return GetUnauthorizedError("Token has no subject")
+	}
+
+	if claims.Tenant != "" && ValidateTenant(claims.Tenant) != nil {
+		return GetUnauthorizedError("Token has an invalid tenant")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"api/util"
+	"testing"
+)
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+func TestValidateTokenClaims(t *testing.T) {
+	const now = int64(1700000000)
+
+	valid := func() util.TokenClaims {
+		return util.TokenClaims{
+			Issuer:   "https://sso.example.com",
+			Subject:  "user-1",
+			Audience: util.TokenAudience{"api"},
+			Expires:  int64Pointer(now + 300),
+			Tenant:   "acme",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(claims *util.TokenClaims)
+		valid  bool
+	}{
+		{"valid", func(claims *util.TokenClaims) {}, true},
+		{"no tenant", func(claims *util.TokenClaims) { claims.Tenant = "" }, true},
+
+		// Audience can be a list that we only have to be in
+		{"audience list", func(claims *util.TokenClaims) { claims.Audience = util.TokenAudience{"web", "api"} }, true},
+		{"other audience", func(claims *util.TokenClaims) { claims.Audience = util.TokenAudience{"web"} }, false},
+		{"no audience", func(claims *util.TokenClaims) { claims.Audience = nil }, false},
+
+		{"other issuer", func(claims *util.TokenClaims) { claims.Issuer = "https://evil.example.com" }, false},
+
+		// Expiry and not before get a minute of leeway each way
+		{"expired within leeway", func(claims *util.TokenClaims) { claims.Expires = int64Pointer(now - 60) }, true},
+		{"expired", func(claims *util.TokenClaims) { claims.Expires = int64Pointer(now - 61) }, false},
+		{"no expiry", func(claims *util.TokenClaims) { claims.Expires = nil }, false},
+		{"not before within leeway", func(claims *util.TokenClaims) { claims.NotBefore = int64Pointer(now + 60) }, true},
+		{"not before", func(claims *util.TokenClaims) { claims.NotBefore = int64Pointer(now + 61) }, false},
+
+		{"no subject", func(claims *util.TokenClaims) { claims.Subject = "" }, false},
+		{"invalid tenant", func(claims *util.TokenClaims) { claims.Tenant = "Acme Inc" }, false},
+	}
+
+	for _, test := range tests {
+		claims := valid()
+		test.change(&claims)
+
+		err := ValidateTokenClaims(claims, "https://sso.example.com", "api", now)
+
+		if test.valid && err != nil {
+			t.Errorf("%s: ValidateTokenClaims returned error %v", test.name, err)
+		}
+
+		if !test.valid {
+			_, isUnauthorized := err.(UnauthorizedError)
+
+			if !isUnauthorized {
+				t.Errorf("%s: ValidateTokenClaims = %v, want an UnauthorizedError", test.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"api/domain"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func validateVariantAxes(axes []string) error {
+
+	if len(axes) > 3 {
+		return errors.New("Too many variant axes, max is 3")
+	}
+
+	axisSet := map[string]struct{}{}
+
+	for _, axis := range axes {
+		if len(axis) == 0 {
+			return errors.New("Variant axis can not be empty")
+		}
+
+		if len(axis) > 16 {
+			return fmt.Errorf("Variant axis (%s) is longer than max of 16 characters", axis)
+		}
+
+		axisSet[strings.ToLower(axis)] = struct{}{}
+	}
+
+	if len(axisSet) < len(axes) {
+		return errors.New("Variant axes not unique")
+	}
+
+	return nil
+}
+
+/*
+The combination is the value of every axis in the order
+of the axes. It is lower cased since attribute values only
+differing in case are already treated as the same value.
+*/
+func getVariantCombination(
+	axes []string,
+	attributes []domain.ProductAttribute,
+) (string, bool) {
+
+	values := make([]string, len(axes))
+
+	for i, axis := range axes {
+		found := false
+
+		for _, attribute := range attributes {
+			if attribute.Name == axis {
+				values[i] = strings.ToLower(attribute.Value)
+				found = true
+			}
+		}
+
+		if !found {
+			return "", false
+		}
+	}
+
+	return strings.Join(values, "\x00"), true
+}
+
+/*
+A variant needs a value for every axis of its parent and no
+two variants of the same parent can share all of them. The
+siblings are all the current variants of the parent, the
+variant itself is skipped when it is among them.
+*/
+func ValidateVariant(
+	id domain.ProductId,
+	parent domain.Product,
+	attributes []domain.ProductAttribute,
+	siblings map[domain.ProductId][]domain.ProductAttribute,
+) error {
+
+	if parent.ParentID != nil || len(parent.VariantAxes) == 0 {
+		return fmt.Errorf("Product (%v) has no variant axes and can't have variants", parent.ProductID)
+	}
+
+	combination, complete := getVariantCombination(parent.VariantAxes, attributes)
+
+	if !complete {
+		return fmt.Errorf(
+			"Variant needs an attribute for each of the axes (%s)",
+			strings.Join(parent.VariantAxes, ", "),
+		)
+	}
+
+	for siblingID, siblingAttributes := range siblings {
+		if siblingID == id {
+			continue
+		}
+
+		siblingCombination, _ := getVariantCombination(parent.VariantAxes, siblingAttributes)
+
+		if siblingCombination == combination {
+			return fmt.Errorf("Product (%v) already is a variant with the same axis values", siblingID)
+		}
+	}
+
+	return nil
+}
@@ -2,26 +2,185 @@ CREATE SCHEMA IF NOT EXISTS `sitoo_test_assignment`
 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
 CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product` (
  `product_id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
  `title` VARCHAR(32) NOT NULL,
  `sku` VARCHAR(32) NOT NULL,
  `description` VARCHAR(1024) NULL,
  `price` DECIMAL(12,2) NOT NULL DEFAULT 0.00,
  `created` DATETIME NOT NULL,
  `last_updated` DATETIME NULL,
+ `parent_id` INT UNSIGNED NULL,
+ `brand_id` INT UNSIGNED NULL,
+ `status` VARCHAR(16) NOT NULL DEFAULT 'active',
  PRIMARY KEY (`product_id`),
- UNIQUE INDEX (`sku` ASC),
+ UNIQUE INDEX (`tenant`, `sku` ASC),
  INDEX (`created`),
- INDEX (`last_updated`)
+ INDEX (`last_updated`),
+ INDEX (`parent_id`),
+ INDEX (`brand_id`),
+ INDEX (`status`)
 );
 CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_barcode` (
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
  `product_id` INT UNSIGNED NOT NULL,
  `barcode` VARCHAR(32) NOT NULL,
  PRIMARY KEY (`product_id`, `barcode`),
- UNIQUE INDEX (`barcode`)
+ UNIQUE INDEX (`tenant`, `barcode`),
+ INDEX (`barcode`)
 );
 CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_attribute` (
  `product_id` INT UNSIGNED NOT NULL,
  `name` VARCHAR(16) NOT NULL,
  `value` VARCHAR(32) NOT NULL,
  PRIMARY KEY (`product_id`, `name`)
-);
\ No newline at end of file
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`barcode_sequence` (
+ `prefix` VARCHAR(11) NOT NULL,
+ `next_reference` BIGINT UNSIGNED NOT NULL,
+ PRIMARY KEY (`prefix`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`attribute_definition` (
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `name` VARCHAR(16) NOT NULL,
+ `data_type` VARCHAR(16) NOT NULL,
+ `unit` VARCHAR(16) NULL,
+ `required` BOOLEAN NOT NULL DEFAULT FALSE,
+ PRIMARY KEY (`tenant`, `name`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`attribute_definition_value` (
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `name` VARCHAR(16) NOT NULL,
+ `value` VARCHAR(32) NOT NULL,
+ `sort_order` INT UNSIGNED NOT NULL,
+ PRIMARY KEY (`tenant`, `name`, `value`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`attribute_definition_value_label` (
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `name` VARCHAR(16) NOT NULL,
+ `value` VARCHAR(32) NOT NULL,
+ `locale` VARCHAR(16) NOT NULL,
+ `label` VARCHAR(64) NOT NULL,
+ PRIMARY KEY (`tenant`, `name`, `value`, `locale`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_translation` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `locale` VARCHAR(16) NOT NULL,
+ `title` VARCHAR(32) NULL,
+ `description` VARCHAR(1024) NULL,
+ PRIMARY KEY (`product_id`, `locale`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_variant_axis` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `name` VARCHAR(16) NOT NULL,
+ `sort_order` INT UNSIGNED NOT NULL,
+ PRIMARY KEY (`product_id`, `name`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`category` (
+ `category_id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `parent_id` INT UNSIGNED NULL,
+ `name` VARCHAR(64) NOT NULL,
+ `sort_order` INT NOT NULL DEFAULT 0,
+ PRIMARY KEY (`category_id`),
+ INDEX (`tenant`, `parent_id`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_category` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `category_id` INT UNSIGNED NOT NULL,
+ PRIMARY KEY (`product_id`, `category_id`),
+ INDEX (`category_id`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`brand` (
+ `brand_id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `name` VARCHAR(64) NOT NULL,
+ `manufacturer` VARCHAR(64) NULL,
+ PRIMARY KEY (`brand_id`),
+ UNIQUE INDEX (`tenant`, `name`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_relation` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `relation_type` VARCHAR(16) NOT NULL,
+ `related_product_id` INT UNSIGNED NOT NULL,
+ `sort_order` INT NOT NULL DEFAULT 0,
+ PRIMARY KEY (`product_id`, `relation_type`, `related_product_id`),
+ INDEX (`related_product_id`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_bundle_component` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `component_product_id` INT UNSIGNED NOT NULL,
+ `quantity` INT UNSIGNED NOT NULL,
+ PRIMARY KEY (`product_id`, `component_product_id`),
+ INDEX (`component_product_id`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`stock_movement` (
+ `movement_id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `product_id` INT UNSIGNED NOT NULL,
+ `location` VARCHAR(32) NOT NULL,
+ `delta` BIGINT NOT NULL,
+ `reason` VARCHAR(16) NOT NULL,
+ `reference` VARCHAR(64) NULL,
+ `created` DATETIME NOT NULL,
+ PRIMARY KEY (`movement_id`),
+ INDEX (`tenant`, `product_id`, `location`),
+ INDEX (`tenant`, `location`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`stock_reservation` (
+ `reservation_id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `product_id` INT UNSIGNED NOT NULL,
+ `location` VARCHAR(32) NOT NULL,
+ `quantity` BIGINT NOT NULL,
+ `status` VARCHAR(16) NOT NULL,
+ `reference` VARCHAR(64) NULL,
+ `created` DATETIME NOT NULL,
+ `expires` DATETIME NOT NULL,
+ PRIMARY KEY (`reservation_id`),
+ INDEX (`tenant`, `product_id`, `location`, `status`),
+ INDEX (`status`, `expires`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`stocktake` (
+ `stocktake_id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `location` VARCHAR(32) NOT NULL,
+ `status` VARCHAR(16) NOT NULL,
+ `created` DATETIME NOT NULL,
+ `committed` DATETIME NULL,
+ PRIMARY KEY (`stocktake_id`),
+ INDEX (`tenant`, `location`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`stocktake_count` (
+ `stocktake_id` BIGINT UNSIGNED NOT NULL,
+ `product_id` INT UNSIGNED NOT NULL,
+ `counted` BIGINT NOT NULL,
+ PRIMARY KEY (`stocktake_id`, `product_id`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`channel` (
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `code` VARCHAR(16) NOT NULL,
+ `name` VARCHAR(64) NOT NULL,
+ PRIMARY KEY (`tenant`, `code`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`product_channel` (
+ `product_id` INT UNSIGNED NOT NULL,
+ `channel` VARCHAR(16) NOT NULL,
+ `published` BOOLEAN NOT NULL DEFAULT FALSE,
+ `title` VARCHAR(32) NULL,
+ `price` DECIMAL(12,2) NULL,
+ PRIMARY KEY (`product_id`, `channel`),
+ INDEX (`channel`, `published`)
+);
+CREATE TABLE IF NOT EXISTS `sitoo_test_assignment`.`api_key` (
+ `key_id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+ `tenant` VARCHAR(32) NOT NULL DEFAULT 'default',
+ `name` VARCHAR(64) NOT NULL,
+ `prefix` VARCHAR(16) NOT NULL,
+ `roles` VARCHAR(128) NOT NULL DEFAULT '',
+ `key_hash` CHAR(64) NOT NULL,
+ `created` DATETIME NOT NULL,
+ `revoked` DATETIME NULL,
+ PRIMARY KEY (`key_id`),
+ UNIQUE INDEX (`key_hash`),
+ INDEX (`tenant`)
+);